@@ -0,0 +1,314 @@
+// Package analytics aggregates pricing results into savings and percent of Medicare summaries.
+package analytics
+
+import (
+	"slices"
+
+	"braces.dev/errtrace"
+	"github.com/mypricehealth/decimal"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// Dimension is a field used to break down pricing results into groups.
+type Dimension string
+
+const (
+	ByMedicareSource        Dimension = "medicareSource"        // Source of the Medicare amount (e.g. MPFS, IPPS, etc.)
+	ByFormType              Dimension = "formType"              // Form type of the claim (HCFA or UB-04)
+	ByBillType              Dimension = "billType"              // Bill type (UB-04) or place of service (HCFA) of the claim
+	ByProviderNPI           Dimension = "providerNPI"           // National Provider Identifier of the billing provider
+	ByProviderCCN           Dimension = "providerCCN"           // CMS Certification Number of the provider used for pricing
+	ByDRG                   Dimension = "drg"                   // DRG used for pricing (inpatient only)
+	ByAPC                   Dimension = "apc"                   // APC used for payment of each service line (outpatient only)
+	ByState                 Dimension = "state"                 // State of the provider
+	ByMedicareRepricingCode Dimension = "medicareRepricingCode" // Claim-level Medicare repricing code
+	ByAllowedRepricingCode  Dimension = "allowedRepricingCode"  // Claim-level allowed repricing code
+)
+
+// Dimensions lists every supported dimension.
+var Dimensions = []Dimension{ByMedicareSource, ByFormType, ByBillType, ByProviderNPI, ByProviderCCN, ByDRG, ByAPC, ByState, ByMedicareRepricingCode, ByAllowedRepricingCode}
+
+// IsLineLevel returns true if the dimension groups individual service lines instead of claims.
+func (d Dimension) IsLineLevel() bool {
+	return d == ByAPC
+}
+
+// Distribution summarizes a set of values using percentiles.
+type Distribution struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	P10    float64 `json:"p10"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+}
+
+// Summary contains totals and percent of Medicare statistics for a group of claims or service lines.
+// Amounts and percentages only include items which were priced (had no error and a Medicare amount
+// greater than zero) so that the ratios are comparable.
+type Summary struct {
+	Count                    int          `json:"count"`                    // Number of claims (or service lines for line-level dimensions)
+	Priced                   int          `json:"priced"`                   // Number which were priced
+	Errors                   int          `json:"errors"`                   // Number which could not be priced due to an error
+	BilledAmount             float64      `json:"billedAmount"`             // Total billed amount
+	MedicareAmount           float64      `json:"medicareAmount"`           // Total Medicare amount
+	AllowedAmount            float64      `json:"allowedAmount"`            // Total allowed amount
+	PaidAmount               float64      `json:"paidAmount"`               // Total paid amount
+	PercentOfMedicareBilled  float64      `json:"percentOfMedicareBilled"`  // Total billed amount as a percent of the total Medicare amount
+	PercentOfMedicareAllowed float64      `json:"percentOfMedicareAllowed"` // Total allowed amount as a percent of the total Medicare amount
+	PercentOfMedicarePaid    float64      `json:"percentOfMedicarePaid"`    // Total paid amount as a percent of the total Medicare amount
+	SavingsVsBilled          float64      `json:"savingsVsBilled"`          // Total billed amount less the total allowed amount
+	SavingsPercent           float64      `json:"savingsPercent"`           // Savings as a percent of the total billed amount
+	BilledDistribution       Distribution `json:"billedDistribution"`       // Distribution of the billed amount as a percent of Medicare
+	AllowedDistribution      Distribution `json:"allowedDistribution"`      // Distribution of the allowed amount as a percent of Medicare
+	PaidDistribution         Distribution `json:"paidDistribution"`         // Distribution of the paid amount as a percent of Medicare
+	SavingsDistribution      Distribution `json:"savingsDistribution"`      // Distribution of savings as a percent of billed
+}
+
+// Group contains the summary for a single value of a dimension.
+type Group struct {
+	Key string `json:"key"`
+	Summary
+}
+
+// Report contains the overall summary as well as the breakdown by each requested dimension.
+type Report struct {
+	Overall    Summary               `json:"overall"`
+	Breakdowns map[Dimension][]Group `json:"breakdowns,omitempty"` // Groups are sorted by key
+}
+
+// item contains the amounts for a single claim or service line.
+type item struct {
+	billed, medicare, allowed, paid float64
+	isError                         bool
+}
+
+func (i item) isPriced() bool {
+	return !i.isError && i.medicare > 0
+}
+
+// Analyze summarizes pricing results along with the claims that were priced. Results must be in the same order as
+// the claims (as returned by the batch pricing endpoints). The allowed amount for each claim or line is the allowed
+// amount from pricing when available, otherwise the plan allowed amount supplied on the claim. If no dimensions are
+// given, only the overall summary is calculated.
+func Analyze(claims []mph.Claim, results []mph.ErrorAndResult[mph.Pricing], dimensions ...Dimension) (Report, error) {
+	if len(claims) != len(results) {
+		return Report{}, errtrace.Errorf("got %d claims but %d pricing results", len(claims), len(results))
+	}
+
+	overall := &accumulator{}
+	groups := make(map[Dimension]map[string]*accumulator, len(dimensions))
+	for _, d := range dimensions {
+		if !slices.Contains(Dimensions, d) {
+			return Report{}, errtrace.Errorf("unknown dimension %q", d)
+		}
+		groups[d] = map[string]*accumulator{}
+	}
+
+	for i, claim := range claims {
+		result := results[i]
+		if claim.ClaimID != "" && result.Result.ClaimID != "" && claim.ClaimID != result.Result.ClaimID {
+			return Report{}, errtrace.Errorf("pricing result %d is for claim %q, expected %q", i, result.Result.ClaimID, claim.ClaimID)
+		}
+		claimItem := item{
+			billed:   claim.BilledAmount,
+			medicare: result.Result.MedicareAmount,
+			allowed:  allowedAmount(result.Result.AllowedAmount, claim.AllowedAmount),
+			paid:     claim.PaidAmount,
+			isError:  result.Error != nil,
+		}
+		overall.add(claimItem)
+		for _, d := range dimensions {
+			if d.IsLineLevel() {
+				addLines(groups[d], claim, result)
+				continue
+			}
+			addToGroup(groups[d], claimKey(d, claim, result.Result), claimItem)
+		}
+	}
+
+	report := Report{Overall: overall.summarize()}
+	if len(dimensions) > 0 {
+		report.Breakdowns = make(map[Dimension][]Group, len(dimensions))
+	}
+	for d, accumulators := range groups {
+		keys := make([]string, 0, len(accumulators))
+		for key := range accumulators {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		breakdown := make([]Group, 0, len(keys))
+		for _, key := range keys {
+			breakdown = append(breakdown, Group{Key: key, Summary: accumulators[key].summarize()})
+		}
+		report.Breakdowns[d] = breakdown
+	}
+	return report, nil
+}
+
+func allowedAmount(priced, claim float64) float64 {
+	if priced != 0 {
+		return priced
+	}
+	return claim
+}
+
+func addToGroup(groups map[string]*accumulator, key string, i item) {
+	a, ok := groups[key]
+	if !ok {
+		a = &accumulator{}
+		groups[key] = a
+	}
+	a.add(i)
+}
+
+// addLines adds each priced service line to the group for its payment APC. Lines are matched to the
+// services on the claim by line number to get the billed, allowed and paid amounts.
+func addLines(groups map[string]*accumulator, claim mph.Claim, result mph.ErrorAndResult[mph.Pricing]) {
+	services := make(map[string]mph.Service, len(claim.Services))
+	for _, s := range claim.Services {
+		services[s.LineNumber] = s
+	}
+	for _, priced := range result.Result.Services {
+		service := services[priced.LineNumber]
+		addToGroup(groups, priced.PaymentAPC, item{
+			billed:   service.BilledAmount,
+			medicare: priced.MedicareAmount,
+			allowed:  allowedAmount(priced.AllowedAmount, service.AllowedAmount),
+			paid:     service.PaidAmount,
+			isError:  result.Error != nil,
+		})
+	}
+}
+
+func claimKey(d Dimension, claim mph.Claim, pricing mph.Pricing) string {
+	switch d {
+	case ByMedicareSource:
+		return string(pricing.MedicareSource)
+	case ByFormType:
+		return string(claim.FormType)
+	case ByBillType:
+		return claim.BillTypeOrPOS
+	case ByProviderNPI:
+		return claim.NPI
+	case ByProviderCCN:
+		if pricing.ProviderDetail.CCN != "" {
+			return pricing.ProviderDetail.CCN
+		}
+		return claim.CCN
+	case ByDRG:
+		if pricing.InpatientPriceDetail.DRG != "" {
+			return pricing.InpatientPriceDetail.DRG
+		}
+		return claim.DRG
+	case ByState:
+		return claim.ProviderState
+	case ByMedicareRepricingCode:
+		return string(pricing.MedicareRepricingCode)
+	case ByAllowedRepricingCode:
+		return string(pricing.AllowedRepricingCode)
+	}
+	return ""
+}
+
+type accumulator struct {
+	summary                        Summary
+	billed, allowed, paid, savings []float64
+	billedTotal, medicareTotal     decimal.Decimal // totals are summed exactly to avoid drift over many items
+	allowedTotal, paidTotal        decimal.Decimal
+}
+
+func (a *accumulator) add(i item) {
+	s := &a.summary
+	s.Count++
+	if i.isError {
+		s.Errors++
+	}
+	if !i.isPriced() {
+		return
+	}
+	s.Priced++
+	a.billedTotal = a.billedTotal.Add(mph.Money(i.billed))
+	a.medicareTotal = a.medicareTotal.Add(mph.Money(i.medicare))
+	a.allowedTotal = a.allowedTotal.Add(mph.Money(i.allowed))
+	a.paidTotal = a.paidTotal.Add(mph.Money(i.paid))
+	if i.billed > 0 {
+		a.billed = append(a.billed, percent(i.billed, i.medicare))
+		a.savings = append(a.savings, percent(i.billed-i.allowed, i.billed))
+	}
+	if i.allowed > 0 {
+		a.allowed = append(a.allowed, percent(i.allowed, i.medicare))
+	}
+	if i.paid > 0 {
+		a.paid = append(a.paid, percent(i.paid, i.medicare))
+	}
+}
+
+func (a *accumulator) summarize() Summary {
+	s := a.summary
+	s.BilledAmount = a.billedTotal.InexactFloat64()
+	s.MedicareAmount = a.medicareTotal.InexactFloat64()
+	s.AllowedAmount = a.allowedTotal.InexactFloat64()
+	s.PaidAmount = a.paidTotal.InexactFloat64()
+	s.PercentOfMedicareBilled = percent(s.BilledAmount, s.MedicareAmount)
+	s.PercentOfMedicareAllowed = percent(s.AllowedAmount, s.MedicareAmount)
+	s.PercentOfMedicarePaid = percent(s.PaidAmount, s.MedicareAmount)
+	s.SavingsVsBilled = a.billedTotal.Sub(a.allowedTotal).InexactFloat64()
+	s.SavingsPercent = percent(s.SavingsVsBilled, s.BilledAmount)
+	s.BilledDistribution = NewDistribution(a.billed)
+	s.AllowedDistribution = NewDistribution(a.allowed)
+	s.PaidDistribution = NewDistribution(a.paid)
+	s.SavingsDistribution = NewDistribution(a.savings)
+	return s
+}
+
+// percent returns value as a percent of total or zero if total is zero.
+func percent(value, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return value / total * 100
+}
+
+// NewDistribution calculates the distribution of values. The values slice is sorted in place.
+func NewDistribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	slices.Sort(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return Distribution{
+		Count:  len(values),
+		Mean:   sum / float64(len(values)),
+		Min:    values[0],
+		P10:    Percentile(values, 10),
+		P25:    Percentile(values, 25),
+		Median: Percentile(values, 50),
+		P75:    Percentile(values, 75),
+		P90:    Percentile(values, 90),
+		Max:    values[len(values)-1],
+	}
+}
+
+// Percentile returns the p-th percentile (0-100) of sorted values using linear interpolation between the closest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	if lower < 0 {
+		return sorted[0]
+	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
+}
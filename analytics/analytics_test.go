@@ -0,0 +1,119 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var claims = []mph.Claim{
+	{ClaimID: "1", Provider: mph.Provider{NPI: "111", ProviderState: "AL"}, FormType: mph.UBFormType, BillTypeOrPOS: "131", BilledAmount: 300, PaidAmount: 150, Services: []mph.Service{
+		{LineNumber: "1", BilledAmount: 200},
+		{LineNumber: "2", BilledAmount: 100},
+	}},
+	{ClaimID: "2", Provider: mph.Provider{NPI: "222", ProviderState: "GA"}, FormType: mph.HCFAFormType, BillTypeOrPOS: "11", BilledAmount: 400, AllowedAmount: 250},
+	{ClaimID: "3", Provider: mph.Provider{NPI: "111", ProviderState: "AL"}, FormType: mph.UBFormType, BillTypeOrPOS: "111", BilledAmount: 5000},
+}
+
+var results = []mph.ErrorAndResult[mph.Pricing]{
+	{Result: mph.Pricing{ClaimID: "1", MedicareAmount: 100, AllowedAmount: 200, MedicareSource: mph.MedicareSourceOutpatient, Services: []mph.PricedService{
+		{LineNumber: "1", MedicareAmount: 80, PaymentAPC: "5012"},
+		{LineNumber: "2", MedicareAmount: 20, PaymentAPC: "5522"},
+	}}},
+	{Result: mph.Pricing{ClaimID: "2", MedicareAmount: 200, MedicareSource: mph.MedicareSourceMPFS}},
+	{Error: &mph.ResponseError{Title: "pricing not available", Detail: "provider not found"}},
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+	report, err := Analyze(claims, results, ByMedicareSource, ByProviderNPI, ByAPC)
+	require.NoError(t, err)
+
+	o := report.Overall
+	assert.Equal(t, 3, o.Count)
+	assert.Equal(t, 2, o.Priced)
+	assert.Equal(t, 1, o.Errors)
+	assert.InDelta(t, 700, o.BilledAmount, 0.001)
+	assert.InDelta(t, 300, o.MedicareAmount, 0.001)
+	assert.InDelta(t, 450, o.AllowedAmount, 0.001)
+	assert.InDelta(t, 150, o.PaidAmount, 0.001)
+	assert.InDelta(t, 233.333, o.PercentOfMedicareBilled, 0.001)
+	assert.InDelta(t, 150, o.PercentOfMedicareAllowed, 0.001)
+	assert.InDelta(t, 50, o.PercentOfMedicarePaid, 0.001)
+	assert.InDelta(t, 250, o.SavingsVsBilled, 0.001)
+	assert.InDelta(t, 35.714, o.SavingsPercent, 0.001)
+	assert.Equal(t, 2, o.BilledDistribution.Count)
+	assert.InDelta(t, 250, o.BilledDistribution.Median, 0.001)
+	assert.InDelta(t, 200, o.BilledDistribution.Min, 0.001)
+	assert.InDelta(t, 300, o.BilledDistribution.Max, 0.001)
+
+	sources := report.Breakdowns[ByMedicareSource]
+	require.Len(t, sources, 3)
+	assert.Equal(t, "", sources[0].Key)
+	assert.Equal(t, 1, sources[0].Errors)
+	assert.Equal(t, string(mph.MedicareSourceMPFS), sources[1].Key)
+	assert.InDelta(t, 125, sources[1].PercentOfMedicareAllowed, 0.001)
+	assert.Equal(t, string(mph.MedicareSourceOutpatient), sources[2].Key)
+
+	npis := report.Breakdowns[ByProviderNPI]
+	require.Len(t, npis, 2)
+	assert.Equal(t, "111", npis[0].Key)
+	assert.Equal(t, 2, npis[0].Count)
+	assert.Equal(t, 1, npis[0].Priced)
+
+	apcs := report.Breakdowns[ByAPC]
+	require.Len(t, apcs, 2)
+	assert.Equal(t, "5012", apcs[0].Key)
+	assert.InDelta(t, 250, apcs[0].PercentOfMedicareBilled, 0.001)
+	assert.Equal(t, "5522", apcs[1].Key)
+	assert.InDelta(t, 500, apcs[1].PercentOfMedicareBilled, 0.001)
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	t.Parallel()
+	_, err := Analyze(claims[:1], results)
+	require.Error(t, err)
+
+	_, err = Analyze(claims[1:2], results[:1])
+	require.Error(t, err)
+
+	_, err = Analyze(claims, results, Dimension("unknown"))
+	require.Error(t, err)
+
+	report, err := Analyze(claims, results)
+	require.NoError(t, err)
+	assert.Nil(t, report.Breakdowns)
+}
+
+func TestNewDistribution(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, Distribution{}, NewDistribution(nil))
+
+	d := NewDistribution([]float64{50, 10, 40, 20, 30})
+	assert.Equal(t, Distribution{Count: 5, Mean: 30, Min: 10, P10: 14, P25: 20, Median: 30, P75: 40, P90: 46, Max: 50}, d)
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+	assert.Zero(t, Percentile(nil, 50))
+	assert.InDelta(t, 7, Percentile([]float64{7}, 90), 0.001)
+	assert.InDelta(t, 15, Percentile([]float64{10, 20}, 50), 0.001)
+	assert.InDelta(t, 20, Percentile([]float64{10, 20}, 100), 0.001)
+}
+
+func TestAnalyzeExactTotals(t *testing.T) {
+	t.Parallel()
+	var claims []mph.Claim
+	var results []mph.ErrorAndResult[mph.Pricing]
+	for range 10 {
+		claims = append(claims, mph.Claim{BilledAmount: 0.1, AllowedAmount: 0.1})
+		results = append(results, mph.ErrorAndResult[mph.Pricing]{Result: mph.Pricing{MedicareAmount: 0.1}})
+	}
+	report, err := Analyze(claims, results)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, report.Overall.BilledAmount)
+	assert.Equal(t, 1.0, report.Overall.MedicareAmount)
+	assert.Equal(t, 0.0, report.Overall.SavingsVsBilled)
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/mypricehealth/mphgo/set"
+)
+
+// diffOptions controls which changes are reported by the diff command.
+type diffOptions struct {
+	AmountThreshold  float64 // minimum absolute change in an amount for it to be reported
+	PercentThreshold float64 // minimum percent change in an amount for it to be reported
+}
+
+const (
+	claimAdded   = "added"   // claim only exists in the new pricing run
+	claimRemoved = "removed" // claim only exists in the old pricing run
+	claimChanged = "changed" // claim exists in both pricing runs, but has changes
+)
+
+// claimDiff describes how a single claim differs between two pricing runs.
+type claimDiff struct {
+	ClaimID  string            `json:"claimID"`
+	Position int               `json:"position,omitzero"` // 1-based position in the pricing runs of results without a claimID
+	Status   string            `json:"status"`
+	Changes  []mph.FieldChange `json:"changes,omitempty"`  // claim-level changes
+	Services []mph.ServiceDiff `json:"services,omitempty"` // services which were added, removed or changed
+}
+
+// amountSummary contains summary statistics for an amount across all matched claims.
+type amountSummary struct {
+	OldTotal    float64  `json:"oldTotal"`
+	NewTotal    float64  `json:"newTotal"`
+	Delta       float64  `json:"delta"`
+	Percent     *float64 `json:"percent,omitempty"`
+	MeanDelta   float64  `json:"meanDelta"`
+	MedianDelta float64  `json:"medianDelta"`
+	MinDelta    float64  `json:"minDelta"`
+	MaxDelta    float64  `json:"maxDelta"`
+}
+
+// diffSummary contains summary statistics for the comparison of two pricing runs.
+type diffSummary struct {
+	OldClaims      int            `json:"oldClaims"`
+	NewClaims      int            `json:"newClaims"`
+	Matched        int            `json:"matched"`
+	Changed        int            `json:"changed"`
+	Added          int            `json:"added"`
+	Removed        int            `json:"removed"`
+	MedicareAmount amountSummary  `json:"medicareAmount"`
+	AllowedAmount  amountSummary  `json:"allowedAmount"`
+	FieldCounts    map[string]int `json:"fieldCounts,omitempty"` // number of claims with a change in each field
+}
+
+// diffReport is the result of comparing two pricing runs.
+type diffReport struct {
+	Summary diffSummary `json:"summary"`
+	Claims  []claimDiff `json:"claims"`
+}
+
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var opts diffOptions
+	flags.Float64Var(&opts.AmountThreshold, "amount", 0.01, "only report amounts which changed by at least this many dollars")
+	flags.Float64Var(&opts.PercentThreshold, "percent", 0, "only report amounts which changed by at least this percent")
+	format := flags.String("format", "text", "output format (text or json)")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, `Usage: mph diff [flags] old.json new.json
+
+Compares two pricing runs, matching claims by claimID and services by lineNumber. Each file
+may contain either a batch pricing response or a JSON array of pricing results. The exit
+status is 0 if no differences were found, 1 if there were differences and 2 on error.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitTrouble
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitTrouble
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "mph diff: unknown format %q\n", *format)
+		return exitTrouble
+	}
+
+	oldResults, err := readPricingRun(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "mph diff: %v\n", err)
+		return exitTrouble
+	}
+	newResults, err := readPricingRun(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "mph diff: %v\n", err)
+		return exitTrouble
+	}
+
+	report, err := diffPricingRuns(oldResults, newResults, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "mph diff: %v\n", err)
+		return exitTrouble
+	}
+
+	if *format == "json" {
+		err = writeJSONReport(stdout, report)
+	} else {
+		err = writeTextReport(stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "mph diff: %v\n", err)
+		return exitTrouble
+	}
+	if len(report.Claims) > 0 {
+		return exitChanged
+	}
+	return exitSame
+}
+
+// readPricingRun reads the results of a pricing run from a file containing either an ErrorAndResultResponses
+// envelope as returned by the batch endpoints or a JSON array of pricing results.
+func readPricingRun(path string) ([]mph.ErrorAndResult[mph.Pricing], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var responses mph.ErrorAndResultResponses[mph.Pricing]
+		if err := mph.Unmarshal(data, &responses); err != nil {
+			return nil, errtrace.Errorf("parsing %s: %w", path, err)
+		}
+		if responses.Error != nil {
+			return nil, errtrace.Errorf("%s contains an error response: %w", path, responses.Error)
+		}
+		return responses.Results, nil
+	}
+
+	var results []mph.ErrorAndResult[mph.Pricing]
+	if err := mph.Unmarshal(data, &results); err != nil {
+		return nil, errtrace.Errorf("parsing %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// claimKey identifies a result in a pricing run by its claimID, or by its position if it has no claimID (such as a
+// result which only holds an error).
+type claimKey struct {
+	claimID  string
+	position int
+}
+
+func resultKey(i int, result mph.ErrorAndResult[mph.Pricing]) claimKey {
+	if result.Result.ClaimID == "" {
+		return claimKey{position: i + 1}
+	}
+	return claimKey{claimID: result.Result.ClaimID}
+}
+
+// indexResults indexes the results of a pricing run by their claimKey.
+func indexResults(results []mph.ErrorAndResult[mph.Pricing]) (map[claimKey]mph.ErrorAndResult[mph.Pricing], error) {
+	index := make(map[claimKey]mph.ErrorAndResult[mph.Pricing], len(results))
+	for i, result := range results {
+		key := resultKey(i, result)
+		if _, ok := index[key]; ok {
+			return nil, errtrace.Errorf("duplicate claimID %q", key.claimID)
+		}
+		index[key] = result
+	}
+	return index, nil
+}
+
+// diffPricingRuns compares two pricing runs, matching claims by ClaimID. Results without a ClaimID are matched by
+// their position in the pricing runs. Claims are reported in the order they appear in the old pricing run followed
+// by any claims which only appear in the new pricing run.
+func diffPricingRuns(oldResults, newResults []mph.ErrorAndResult[mph.Pricing], opts diffOptions) (diffReport, error) {
+	oldIndex, err := indexResults(oldResults)
+	if err != nil {
+		return diffReport{}, errtrace.Errorf("old pricing run: %w", err)
+	}
+	newIndex, err := indexResults(newResults)
+	if err != nil {
+		return diffReport{}, errtrace.Errorf("new pricing run: %w", err)
+	}
+
+	report := diffReport{Summary: diffSummary{OldClaims: len(oldResults), NewClaims: len(newResults), FieldCounts: map[string]int{}}}
+	var medicareDeltas, allowedDeltas []float64
+	for i, oldResult := range oldResults {
+		key := resultKey(i, oldResult)
+		newResult, ok := newIndex[key]
+		if !ok {
+			report.Summary.Removed++
+			report.Claims = append(report.Claims, claimDiff{ClaimID: key.claimID, Position: key.position, Status: claimRemoved})
+			continue
+		}
+
+		report.Summary.Matched++
+		report.Summary.MedicareAmount.OldTotal += oldResult.Result.MedicareAmount
+		report.Summary.MedicareAmount.NewTotal += newResult.Result.MedicareAmount
+		report.Summary.AllowedAmount.OldTotal += oldResult.Result.AllowedAmount
+		report.Summary.AllowedAmount.NewTotal += newResult.Result.AllowedAmount
+		medicareDeltas = append(medicareDeltas, newResult.Result.MedicareAmount-oldResult.Result.MedicareAmount)
+		allowedDeltas = append(allowedDeltas, newResult.Result.AllowedAmount-oldResult.Result.AllowedAmount)
+
+		diff := diffResults(oldResult, newResult, opts)
+		if diff.IsEmpty() {
+			continue
+		}
+		report.Summary.Changed++
+		for _, field := range changedFields(diff) {
+			report.Summary.FieldCounts[field]++
+		}
+		report.Claims = append(report.Claims, claimDiff{ClaimID: key.claimID, Position: key.position, Status: claimChanged, Changes: diff.Changes, Services: diff.Services})
+	}
+	for i, newResult := range newResults {
+		key := resultKey(i, newResult)
+		if _, ok := oldIndex[key]; !ok {
+			report.Summary.Added++
+			report.Claims = append(report.Claims, claimDiff{ClaimID: key.claimID, Position: key.position, Status: claimAdded})
+		}
+	}
+
+	summarizeAmounts(&report.Summary.MedicareAmount, medicareDeltas)
+	summarizeAmounts(&report.Summary.AllowedAmount, allowedDeltas)
+	return report, nil
+}
+
+func summarizeAmounts(s *amountSummary, deltas []float64) {
+	s.Delta = s.NewTotal - s.OldTotal
+	s.Percent = mph.PercentChange(s.OldTotal, s.NewTotal)
+	if len(deltas) == 0 {
+		return
+	}
+	slices.Sort(deltas)
+	var sum float64
+	for _, d := range deltas {
+		sum += d
+	}
+	s.MeanDelta = sum / float64(len(deltas))
+	s.MinDelta = deltas[0]
+	s.MaxDelta = deltas[len(deltas)-1]
+	mid := len(deltas) / 2
+	if len(deltas)%2 == 0 {
+		s.MedianDelta = (deltas[mid-1] + deltas[mid]) / 2
+	} else {
+		s.MedianDelta = deltas[mid]
+	}
+}
+
+// diffResults compares two pricing results for the same claim, dropping any amount changes which are below
+// the thresholds in opts.
+func diffResults(oldResult, newResult mph.ErrorAndResult[mph.Pricing], opts diffOptions) mph.PricingDiff {
+	diff := mph.DiffPricing(oldResult.Result, newResult.Result)
+	diff.Changes = filterChanges(diff.Changes, opts)
+	if oldErr, newErr := oldResult.Error.Error(), newResult.Error.Error(); oldErr != newErr {
+		diff.Changes = append([]mph.FieldChange{{Path: "error", Old: oldErr, New: newErr}}, diff.Changes...)
+	}
+
+	services := diff.Services[:0]
+	for _, service := range diff.Services {
+		service.Changes = filterChanges(service.Changes, opts)
+		if service.Kind != mph.ChangeModified || len(service.Changes) > 0 {
+			services = append(services, service)
+		}
+	}
+	diff.Services = services
+	return diff
+}
+
+func filterChanges(changes []mph.FieldChange, opts diffOptions) []mph.FieldChange {
+	filtered := changes[:0]
+	for _, c := range changes {
+		if c.IsAmount() && (math.Abs(c.Delta) < opts.AmountThreshold || c.Percent != nil && math.Abs(*c.Percent) < opts.PercentThreshold) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+// changedFields returns the distinct fields which changed in diff. Service-level fields are prefixed with "services."
+// and services which were added or removed are counted as a change to "services".
+func changedFields(diff mph.PricingDiff) []string {
+	fields := set.NewOrderedSet[string]()
+	for _, c := range diff.Changes {
+		fields.AddItems(c.Path)
+	}
+	for _, service := range diff.Services {
+		if service.Kind != mph.ChangeModified {
+			fields.AddItems("services")
+		}
+		for _, c := range service.Changes {
+			fields.AddItems("services." + c.Path)
+		}
+	}
+	return fields.Items()
+}
+
+func writeJSONReport(w io.Writer, report diffReport) error {
+	enc := jsontext.NewEncoder(w, jsontext.WithIndent("  "))
+	return errtrace.Wrap(json.MarshalEncode(enc, report, json.Deterministic(true)))
+}
+
+func writeTextReport(w io.Writer, report diffReport) error {
+	var buf bytes.Buffer
+	for _, claim := range report.Claims {
+		fmt.Fprintf(&buf, "%s %s\n", claimLabel(claim), claim.Status)
+		for _, c := range claim.Changes {
+			writeTextChange(&buf, "", c)
+		}
+		for _, service := range claim.Services {
+			if service.Kind != mph.ChangeModified {
+				fmt.Fprintf(&buf, "  %s %s\n", serviceLabel(service), service.Kind)
+			}
+			for _, c := range service.Changes {
+				writeTextChange(&buf, serviceLabel(service), c)
+			}
+		}
+	}
+	if len(report.Claims) > 0 {
+		buf.WriteString("\n")
+	}
+
+	s := report.Summary
+	fmt.Fprintf(&buf, "claims: %d old, %d new, %d matched, %d changed, %d removed, %d added\n", s.OldClaims, s.NewClaims, s.Matched, s.Changed, s.Removed, s.Added)
+	writeAmountSummary(&buf, "medicareAmount", s.MedicareAmount)
+	writeAmountSummary(&buf, "allowedAmount", s.AllowedAmount)
+	fields := make([]string, 0, len(s.FieldCounts))
+	for field := range s.FieldCounts {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	for _, field := range fields {
+		fmt.Fprintf(&buf, "%s changed on %d claims\n", field, s.FieldCounts[field])
+	}
+	_, err := w.Write(buf.Bytes())
+	return errtrace.Wrap(err)
+}
+
+// claimLabel identifies a claim by its claimID, or by its position if it has none.
+func claimLabel(c claimDiff) string {
+	if c.ClaimID == "" {
+		return fmt.Sprintf("claim at position %d", c.Position)
+	}
+	return "claim " + c.ClaimID
+}
+
+// serviceLabel identifies a service by its line number, and also by its position when services were aligned by
+// position.
+func serviceLabel(s mph.ServiceDiff) string {
+	switch {
+	case s.Position == 0:
+		return "line " + s.LineNumber
+	case s.LineNumber == "":
+		return fmt.Sprintf("position %d", s.Position)
+	}
+	return fmt.Sprintf("line %s (position %d)", s.LineNumber, s.Position)
+}
+
+func writeTextChange(buf *bytes.Buffer, label string, c mph.FieldChange) {
+	buf.WriteString("  ")
+	if label != "" {
+		buf.WriteString(label + " ")
+	}
+	fmt.Fprintf(buf, "%s: %s -> %s", c.Path, formatValue(c.Old), formatValue(c.New))
+	if c.Delta != 0 {
+		fmt.Fprintf(buf, " (%s)", formatDelta(c.Delta, c.Percent))
+	}
+	buf.WriteString("\n")
+}
+
+func writeAmountSummary(buf *bytes.Buffer, name string, s amountSummary) {
+	fmt.Fprintf(buf, "%s: %.2f -> %.2f (%s), per claim mean %+.2f, median %+.2f, min %+.2f, max %+.2f\n",
+		name, s.OldTotal, s.NewTotal, formatDelta(s.Delta, s.Percent), s.MeanDelta, s.MedianDelta, s.MinDelta, s.MaxDelta)
+}
+
+func formatDelta(delta float64, percent *float64) string {
+	if percent == nil {
+		return fmt.Sprintf("%+.2f", delta)
+	}
+	return fmt.Sprintf("%+.2f, %+.2f%%", delta, *percent)
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	}
+	if s := fmt.Sprint(v); s != "" {
+		return s
+	}
+	return `""`
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var oldRun = []mph.ErrorAndResult[mph.Pricing]{
+	{Result: mph.Pricing{ClaimID: "1", MedicareAmount: 100, AllowedAmount: 150, MedicareSource: mph.MedicareSourceMPFS, Services: []mph.PricedService{
+		{LineNumber: "1", MedicareAmount: 60, MedicareRepricingCode: mph.LineRepricingCodeMedicare},
+		{LineNumber: "2", MedicareAmount: 40, MedicareRepricingCode: mph.LineRepricingCodeMedicare},
+	}}},
+	{Result: mph.Pricing{ClaimID: "2", MedicareAmount: 200, AllowedAmount: 300}},
+	{Result: mph.Pricing{ClaimID: "3", MedicareAmount: 50}},
+}
+
+var newRun = []mph.ErrorAndResult[mph.Pricing]{
+	{Result: mph.Pricing{ClaimID: "1", MedicareAmount: 110, AllowedAmount: 150, MedicareSource: mph.MedicareSourceMPFS, Services: []mph.PricedService{
+		{LineNumber: "1", MedicareAmount: 70, MedicareRepricingCode: mph.LineRepricingCodeSyntheticMedicare},
+		{LineNumber: "3", MedicareAmount: 40, MedicareRepricingCode: mph.LineRepricingCodeMedicare},
+	}}},
+	{Result: mph.Pricing{ClaimID: "2", MedicareAmount: 200.001, AllowedAmount: 300}},
+	{Result: mph.Pricing{ClaimID: "4", MedicareAmount: 75}},
+}
+
+func TestDiffPricingRuns(t *testing.T) {
+	t.Parallel()
+	report, err := diffPricingRuns(oldRun, newRun, diffOptions{AmountThreshold: 0.01})
+	require.NoError(t, err)
+
+	tenPercent := 10.0
+	sixteenPercent := 100.0 / 6
+	require.Len(t, report.Claims, 3)
+	claim := report.Claims[0]
+	assert.Equal(t, "1", claim.ClaimID)
+	assert.Equal(t, claimChanged, claim.Status)
+	assertChanges(t, []mph.FieldChange{{Path: "medicareAmount", Old: 100.0, New: 110.0, Delta: 10, Percent: &tenPercent}}, claim.Changes)
+	require.Len(t, claim.Services, 3)
+	assert.Equal(t, "1", claim.Services[0].LineNumber)
+	assert.Equal(t, mph.ChangeModified, claim.Services[0].Kind)
+	assertChanges(t, []mph.FieldChange{
+		{Path: "medicareAmount", Old: 60.0, New: 70.0, Delta: 10, Percent: &sixteenPercent},
+		{Path: "medicareRepricingCode", Old: mph.LineRepricingCodeMedicare, New: mph.LineRepricingCodeSyntheticMedicare},
+	}, claim.Services[0].Changes)
+	assert.Equal(t, mph.ServiceDiff{LineNumber: "2", Kind: mph.ChangeRemoved}, claim.Services[1])
+	assert.Equal(t, mph.ServiceDiff{LineNumber: "3", Kind: mph.ChangeAdded}, claim.Services[2])
+	assert.Equal(t, claimDiff{ClaimID: "3", Status: claimRemoved}, report.Claims[1])
+	assert.Equal(t, claimDiff{ClaimID: "4", Status: claimAdded}, report.Claims[2])
+
+	s := report.Summary
+	assert.Equal(t, 3, s.OldClaims)
+	assert.Equal(t, 3, s.NewClaims)
+	assert.Equal(t, 2, s.Matched)
+	assert.Equal(t, 1, s.Changed)
+	assert.Equal(t, 1, s.Added)
+	assert.Equal(t, 1, s.Removed)
+	assert.InDelta(t, 300, s.MedicareAmount.OldTotal, 0.0001)
+	assert.InDelta(t, 310.001, s.MedicareAmount.NewTotal, 0.0001)
+	assert.InDelta(t, 5.0005, s.MedicareAmount.MeanDelta, 0.0001)
+	assert.InDelta(t, 10, s.MedicareAmount.MaxDelta, 0.0001)
+	assert.Equal(t, map[string]int{"medicareAmount": 1, "services": 1, "services.medicareAmount": 1, "services.medicareRepricingCode": 1}, s.FieldCounts)
+}
+
+func assertChanges(t *testing.T, expected, actual []mph.FieldChange) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for i, c := range expected {
+		assert.Equal(t, c.Path, actual[i].Path)
+		assert.Equal(t, c.Old, actual[i].Old)
+		assert.Equal(t, c.New, actual[i].New)
+		assert.InDelta(t, c.Delta, actual[i].Delta, 0.0001)
+		if c.Percent == nil {
+			assert.Nil(t, actual[i].Percent)
+		} else {
+			require.NotNil(t, actual[i].Percent)
+			assert.InDelta(t, *c.Percent, *actual[i].Percent, 0.0001)
+		}
+	}
+}
+
+func TestDiffPricingRunsThresholds(t *testing.T) {
+	t.Parallel()
+	report, err := diffPricingRuns(oldRun[:1], newRun[:1], diffOptions{AmountThreshold: 0.01, PercentThreshold: 12})
+	require.NoError(t, err)
+	require.Len(t, report.Claims, 1)
+	assert.Empty(t, report.Claims[0].Changes, "10% claim-level change should be below the threshold")
+}
+
+func TestDiffPricingRunsDuplicateClaimID(t *testing.T) {
+	t.Parallel()
+	_, err := diffPricingRuns([]mph.ErrorAndResult[mph.Pricing]{oldRun[0], oldRun[0]}, newRun, diffOptions{})
+	require.Error(t, err)
+}
+
+func TestDiffPricingRunsWithoutClaimID(t *testing.T) {
+	t.Parallel()
+	failed := mph.ErrorAndResult[mph.Pricing]{Error: &mph.ResponseError{Title: "claim could not be priced"}}
+	oldResults := []mph.ErrorAndResult[mph.Pricing]{oldRun[1], failed}
+	newResults := []mph.ErrorAndResult[mph.Pricing]{newRun[1], {Result: mph.Pricing{ClaimID: "5", MedicareAmount: 80}}}
+	report, err := diffPricingRuns(oldResults, newResults, diffOptions{AmountThreshold: 0.01})
+	require.NoError(t, err)
+	assert.Equal(t, []claimDiff{{Position: 2, Status: claimRemoved}, {ClaimID: "5", Status: claimAdded}}, report.Claims)
+	assert.Equal(t, 1, report.Summary.Matched)
+
+	// results without a claimID at the same position are compared
+	report, err = diffPricingRuns([]mph.ErrorAndResult[mph.Pricing]{failed}, []mph.ErrorAndResult[mph.Pricing]{{Result: mph.Pricing{MedicareAmount: 80}}}, diffOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.Claims, 1)
+	assert.Equal(t, 1, report.Claims[0].Position)
+	assert.Equal(t, claimChanged, report.Claims[0].Status)
+	assert.Equal(t, "error", report.Claims[0].Changes[0].Path)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTextReport(&buf, report))
+	assert.Contains(t, buf.String(), "claim at position 1 changed\n  error: ")
+}
+
+func TestWriteTextReportPositions(t *testing.T) {
+	t.Parallel()
+	report := diffReport{Claims: []claimDiff{{ClaimID: "1", Status: "changed", Services: []mph.ServiceDiff{
+		{LineNumber: "1", Position: 2, Kind: mph.ChangeModified, Changes: []mph.FieldChange{{Path: "medicareAmount", Old: 40.0, New: 45.0, Delta: 5}}},
+		{Position: 3, Kind: mph.ChangeRemoved},
+	}}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTextReport(&buf, report))
+	assert.Contains(t, buf.String(), "claim 1 changed\n  line 1 (position 2) medicareAmount: 40.00 -> 45.00 (+5.00)\n  position 3 removed\n")
+}
+
+func TestRunDiff(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	oldPath := writeJSONFile(t, dir, "old.json", mph.ErrorAndResultResponses[mph.Pricing]{Results: oldRun, StatusCode: 200})
+	newPath := writeJSONFile(t, dir, "new.json", newRun)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitChanged, run([]string{"diff", oldPath, newPath}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	assert.Contains(t, stdout.String(), "claim 1 changed\n  medicareAmount: 100.00 -> 110.00 (+10.00, +10.00%)\n")
+	assert.Contains(t, stdout.String(), "claims: 3 old, 3 new, 2 matched, 1 changed, 1 removed, 1 added\n")
+
+	stdout.Reset()
+	assert.Equal(t, exitChanged, run([]string{"diff", "-format", "json", oldPath, newPath}, &stdout, &stderr))
+	var report diffReport
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.Equal(t, 1, report.Summary.Changed)
+
+	stdout.Reset()
+	assert.Equal(t, exitSame, run([]string{"diff", oldPath, oldPath}, &stdout, &stderr))
+
+	assert.Equal(t, exitTrouble, run([]string{"diff", oldPath}, &stdout, &stderr))
+	assert.Equal(t, exitTrouble, run([]string{"diff", oldPath, filepath.Join(dir, "missing.json")}, &stdout, &stderr))
+	assert.Equal(t, exitTrouble, run([]string{"unknown"}, &stdout, &stderr))
+}
+
+func writeJSONFile(t *testing.T, dir, name string, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
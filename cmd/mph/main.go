@@ -0,0 +1,50 @@
+// Command mph provides command line tools for working with My Price Health pricing results.
+//
+// Usage:
+//
+//	mph diff [flags] old.json new.json
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// exit codes follow the conventions of diff(1)
+const (
+	exitSame    = 0 // no differences were found
+	exitChanged = 1 // differences were found
+	exitTrouble = 2 // the command could not be completed
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitTrouble
+	}
+	switch args[0] {
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitSame
+	}
+	fmt.Fprintf(stderr, "mph: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitTrouble
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: mph <command> [arguments]
+
+Commands:
+  diff    compare two pricing runs and report which claims changed
+
+Run "mph <command> -h" for more information about a command.
+`)
+}
@@ -0,0 +1,202 @@
+// Package contract calculates allowed amounts locally from Medicare pricing results using declarative
+// contract or reference based pricing (RBP) terms.
+package contract
+
+import (
+	"slices"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// Scope determines whether a rule prices each service line or the claim as a whole.
+type Scope string
+
+const (
+	ScopeLine  Scope = "line"  // price each matching service line (default)
+	ScopeClaim Scope = "claim" // price the entire claim (e.g. DRG case rates or per diems) and allocate to lines by billed amount
+)
+
+// Contract describes how allowed amounts are calculated for a network, provider or RBP arrangement.
+type Contract struct {
+	Name          string                 `json:"name"`                   // Name of the contract used in repricing notes
+	NetworkCode   string                 `json:"networkCode,omitzero"`   // Code describing the network, copied to the pricing results
+	RepricingCode mph.ClaimRepricingCode `json:"repricingCode,omitzero"` // Claim-level allowed repricing code (defaults to CON)
+	LimitToBilled bool                   `json:"limitToBilled,omitzero"` // Set to true to pay the lesser of the contract amount and the billed amount (when billed)
+	Rules         []Rule                 `json:"rules"`                  // Rules are evaluated in order and the first matching rule is used
+}
+
+// Rule matches claims and service lines and defines the formula used to calculate their allowed amount.
+// All criteria which are supplied must match. A rule without any criteria matches everything.
+type Rule struct {
+	Name           string                      `json:"name"`                    // Name of the rule used in repricing notes
+	Scope          Scope                       `json:"scope,omitzero"`          // Whether the rule prices lines or the entire claim
+	NPIs           []string                    `json:"npis,omitzero"`           // Billing provider NPIs
+	CCNs           []string                    `json:"ccns,omitzero"`           // CMS Certification Numbers of the provider
+	FormType       mph.FormType                `json:"formType,omitzero"`       // Form type of the claim
+	BillTypes      []string                    `json:"billTypes,omitzero"`      // Bill type or place of service prefixes (e.g. "11" matches "111" and "117")
+	DRGs           []CodeRange                 `json:"drgs,omitzero"`           // DRGs used for pricing
+	RevenueCodes   []CodeRange                 `json:"revenueCodes,omitzero"`   // Revenue codes of the service line (line scope only)
+	ProcedureCodes []CodeRange                 `json:"procedureCodes,omitzero"` // Procedure codes of the service line (line scope only)
+	Formula        mph.AllowedRepricingFormula `json:"formula"`                 // Formula used to calculate the allowed amount. Percents are whole numbers (e.g. 180 for 180%)
+	FeeSchedule    map[string]float64          `json:"feeSchedule,omitzero"`    // Per unit amounts by procedure code, overriding Formula.FeeSchedule. If the formula is otherwise empty, only lines with a procedure code in the fee schedule match
+}
+
+// CodeRange is an inclusive range of codes such as procedure codes, revenue codes or DRGs. Codes are compared
+// as strings after revenue codes and DRGs have been padded. A range with only From set matches a single code.
+type CodeRange struct {
+	From    string
+	Through string
+}
+
+// ParseCodeRange parses a single code (e.g. "99213") or a range of codes (e.g. "99202-99215").
+func ParseCodeRange(s string) (CodeRange, error) {
+	from, through, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	from, through = strings.TrimSpace(from), strings.TrimSpace(through)
+	if from == "" || isRange && through == "" {
+		return CodeRange{}, errtrace.Errorf("invalid code range %q", s)
+	}
+	if isRange && len(from) == len(through) && through < from {
+		return CodeRange{}, errtrace.Errorf("invalid code range %q: %s is after %s", s, from, through)
+	}
+	return CodeRange{From: from, Through: through}, nil
+}
+
+func (r CodeRange) String() string {
+	if r.Through == "" {
+		return r.From
+	}
+	return r.From + "-" + r.Through
+}
+
+func (r CodeRange) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *CodeRange) UnmarshalText(data []byte) error {
+	parsed, err := ParseCodeRange(string(data))
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	*r = parsed
+	return nil
+}
+
+// Contains returns true if code is within the range.
+func (r CodeRange) Contains(code string) bool {
+	if r.Through == "" {
+		return code == r.From
+	}
+	return code >= r.From && code <= r.Through
+}
+
+// Validate checks that the contract can be used for pricing.
+func (c Contract) Validate() error {
+	if len(c.Rules) == 0 {
+		return errtrace.Errorf("contract %q has no rules", c.Name)
+	}
+	if c.RepricingCode != "" && !c.RepricingCode.IsValid() {
+		return errtrace.Errorf("contract %q has invalid repricing code %q", c.Name, c.RepricingCode)
+	}
+	for i, rule := range c.Rules {
+		if err := rule.validate(); err != nil {
+			return errtrace.Errorf("contract %q rule %d (%s): %w", c.Name, i+1, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	switch r.Scope {
+	case "", ScopeLine:
+	case ScopeClaim:
+		if len(r.RevenueCodes) > 0 || len(r.ProcedureCodes) > 0 {
+			return errtrace.Errorf("claim scope rules cannot match revenue or procedure codes")
+		}
+		if len(r.FeeSchedule) > 0 || r.Formula.FeeSchedule != 0 {
+			return errtrace.Errorf("claim scope rules cannot use a fee schedule")
+		}
+	default:
+		return errtrace.Errorf("invalid scope %q", r.Scope)
+	}
+	f := r.Formula
+	if f.MedicarePercent < 0 || f.BilledPercent < 0 || f.FeeSchedule < 0 || f.FixedAmount < 0 || f.PerDiem < 0 {
+		return errtrace.Errorf("formula cannot contain negative values")
+	}
+	if f.IsEmpty() && len(r.FeeSchedule) == 0 {
+		return errtrace.Errorf("formula is empty")
+	}
+	return nil
+}
+
+func (r Rule) scope() Scope {
+	if r.Scope == "" {
+		return ScopeLine
+	}
+	return r.Scope
+}
+
+func (r Rule) matchesClaim(claim mph.Claim, pricing mph.Pricing) bool {
+	if len(r.NPIs) > 0 && !slices.Contains(r.NPIs, claim.NPI) {
+		return false
+	}
+	if len(r.CCNs) > 0 && !slices.Contains(r.CCNs, pricing.ProviderDetail.CCN) && !slices.Contains(r.CCNs, claim.CCN) {
+		return false
+	}
+	if r.FormType != "" && r.FormType != claim.FormType {
+		return false
+	}
+	if len(r.BillTypes) > 0 && !hasPrefix(r.BillTypes, strings.TrimLeft(claim.BillTypeOrPOS, "0")) {
+		return false
+	}
+	drg := pricing.InpatientPriceDetail.DRG
+	if drg == "" {
+		drg = claim.DRG
+	}
+	return len(r.DRGs) == 0 || matchesCode(r.DRGs, drg, 3)
+}
+
+func (r Rule) matchesLine(service mph.Service) bool {
+	if len(r.FeeSchedule) > 0 && r.Formula.IsEmpty() {
+		if _, ok := r.FeeSchedule[service.ProcedureCode]; !ok {
+			return false
+		}
+	}
+	if len(r.RevenueCodes) > 0 && !matchesCode(r.RevenueCodes, service.RevCode, 4) {
+		return false
+	}
+	return len(r.ProcedureCodes) == 0 || matchesCode(r.ProcedureCodes, service.ProcedureCode, 0)
+}
+
+func hasPrefix(prefixes []string, s string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, strings.TrimLeft(prefix, "0")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCode returns true if code is within any of ranges. Codes and ranges are left padded with zeros
+// to length so that numeric codes such as revenue codes and DRGs compare correctly.
+func matchesCode(ranges []CodeRange, code string, length int) bool {
+	if code == "" {
+		return false
+	}
+	code = padCode(code, length)
+	for _, r := range ranges {
+		padded := CodeRange{From: padCode(r.From, length), Through: padCode(r.Through, length)}
+		if padded.Contains(code) {
+			return true
+		}
+	}
+	return false
+}
+
+func padCode(code string, length int) string {
+	if code == "" || len(code) >= length {
+		return code
+	}
+	return strings.Repeat("0", length-len(code)) + code
+}
@@ -0,0 +1,97 @@
+package contract
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodeRange(t *testing.T) {
+	t.Parallel()
+	r, err := ParseCodeRange("99202-99215")
+	require.NoError(t, err)
+	assert.Equal(t, CodeRange{From: "99202", Through: "99215"}, r)
+	assert.True(t, r.Contains("99213"))
+	assert.False(t, r.Contains("99201"))
+	assert.Equal(t, "99202-99215", r.String())
+
+	r, err = ParseCodeRange(" 0450 ")
+	require.NoError(t, err)
+	assert.Equal(t, CodeRange{From: "0450"}, r)
+	assert.True(t, r.Contains("0450"))
+	assert.False(t, r.Contains("0451"))
+
+	for _, invalid := range []string{"", "-", "99215-", "99215-99202"} {
+		_, err = ParseCodeRange(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestContractJSON(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{
+		"name": "Acme",
+		"limitToBilled": true,
+		"rules": [
+			{"name": "ER", "revenueCodes": ["450-459"], "formula": {"medicarePercent": 200}},
+			{"name": "Inpatient", "scope": "claim", "billTypes": ["11"], "drgs": ["1-999"], "formula": {"medicarePercent": 150, "billedPercent": 60}}
+		]
+	}`)
+	var c Contract
+	require.NoError(t, json.Unmarshal(data, &c))
+	require.NoError(t, c.Validate())
+	assert.Equal(t, []CodeRange{{From: "450", Through: "459"}}, c.Rules[0].RevenueCodes)
+	assert.Equal(t, ScopeClaim, c.Rules[1].Scope)
+
+	out, err := json.Marshal(c.Rules[0].RevenueCodes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["450-459"]`, string(out))
+
+	require.Error(t, json.Unmarshal([]byte(`{"rules":[{"drgs":["300-200"]}]}`), &c))
+}
+
+func TestContractValidate(t *testing.T) {
+	t.Parallel()
+	formula := mph.AllowedRepricingFormula{MedicarePercent: 150}
+	tests := []struct {
+		name     string
+		contract Contract
+	}{
+		{"no rules", Contract{Name: "none"}},
+		{"invalid repricing code", Contract{RepricingCode: "XYZ", Rules: []Rule{{Formula: formula}}}},
+		{"empty formula", Contract{Rules: []Rule{{}}}},
+		{"negative formula", Contract{Rules: []Rule{{Formula: mph.AllowedRepricingFormula{BilledPercent: -1}}}}},
+		{"invalid scope", Contract{Rules: []Rule{{Scope: "other", Formula: formula}}}},
+		{"claim scope with procedure codes", Contract{Rules: []Rule{{Scope: ScopeClaim, ProcedureCodes: []CodeRange{{From: "99213"}}, Formula: formula}}}},
+		{"claim scope with fee schedule", Contract{Rules: []Rule{{Scope: ScopeClaim, FeeSchedule: map[string]float64{"99213": 80}}}}},
+	}
+	for _, test := range tests {
+		assert.Error(t, test.contract.Validate(), test.name)
+	}
+	assert.NoError(t, Contract{Rules: []Rule{{FeeSchedule: map[string]float64{"99213": 80}}}}.Validate())
+}
+
+func TestRuleMatches(t *testing.T) {
+	t.Parallel()
+	claim := mph.Claim{Provider: mph.Provider{NPI: "123", CCN: "010001"}, FormType: mph.UBFormType, BillTypeOrPOS: "0111", DRG: "57"}
+	pricing := mph.Pricing{InpatientPriceDetail: mph.InpatientPriceDetail{DRG: "057"}}
+
+	assert.True(t, Rule{}.matchesClaim(claim, pricing))
+	assert.True(t, Rule{NPIs: []string{"999", "123"}, CCNs: []string{"010001"}, FormType: mph.UBFormType, BillTypes: []string{"11"}, DRGs: []CodeRange{{From: "1", Through: "100"}}}.matchesClaim(claim, pricing))
+	assert.False(t, Rule{NPIs: []string{"999"}}.matchesClaim(claim, pricing))
+	assert.False(t, Rule{CCNs: []string{"999"}}.matchesClaim(claim, pricing))
+	assert.False(t, Rule{FormType: mph.HCFAFormType}.matchesClaim(claim, pricing))
+	assert.False(t, Rule{BillTypes: []string{"13"}}.matchesClaim(claim, pricing))
+	assert.False(t, Rule{DRGs: []CodeRange{{From: "100", Through: "200"}}}.matchesClaim(claim, pricing))
+
+	service := mph.Service{RevCode: "450", ProcedureCode: "99284"}
+	assert.True(t, Rule{RevenueCodes: []CodeRange{{From: "0450", Through: "0459"}}, ProcedureCodes: []CodeRange{{From: "99281", Through: "99285"}}}.matchesLine(service))
+	assert.False(t, Rule{RevenueCodes: []CodeRange{{From: "0360"}}}.matchesLine(service))
+	assert.False(t, Rule{ProcedureCodes: []CodeRange{{From: "99213"}}}.matchesLine(service))
+	assert.False(t, Rule{ProcedureCodes: []CodeRange{{From: "99213"}}}.matchesLine(mph.Service{}))
+	assert.False(t, Rule{FeeSchedule: map[string]float64{"99213": 80}}.matchesLine(service))
+	assert.True(t, Rule{FeeSchedule: map[string]float64{"99284": 80}}.matchesLine(service))
+}
@@ -0,0 +1,228 @@
+package contract
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// zeroDollarCodes are Medicare repricing codes which explain why a line was priced at $0. When a line is allowed
+// at a percent of a $0 Medicare amount, the Medicare explanation is carried over to the allowed repricing code.
+var zeroDollarCodes = map[mph.LineRepricingCode]struct{}{
+	mph.LineRepricingCodeNotRepricedPerRequest: {},
+	mph.LineRepricingCodeNotAllowedByMedicare:  {},
+	mph.LineRepricingCodePackaged:              {},
+	mph.LineRepricingCodeNeedsMoreInfo:         {},
+	mph.LineRepricingCodeProcedureCodeProblem:  {},
+	mph.LineRepricingCodeOutOfNetwork:          {},
+}
+
+// amounts are the inputs to a formula.
+type amounts struct {
+	medicare float64
+	billed   float64
+	units    float64 // quantity of the service line (1 for claims)
+	days     float64 // days used for per diem rates
+}
+
+// allowed is the result of evaluating a formula.
+type allowed struct {
+	amount  float64
+	code    mph.LineRepricingCode
+	formula mph.AllowedRepricingFormula
+	note    string
+}
+
+// Price calculates the allowed amounts for a claim from the Medicare amounts in pricing. It returns a copy of pricing
+// with the claim and line allowed amounts, allowed repricing codes, notes and formulas replaced. Lines are matched to
+// the services on the claim by line number to get the billed amount and quantity.
+func (c Contract) Price(claim mph.Claim, pricing mph.Pricing) (mph.Pricing, error) {
+	if err := c.Validate(); err != nil {
+		return pricing, errtrace.Wrap(err)
+	}
+	if claim.ClaimID != "" && pricing.ClaimID != "" && claim.ClaimID != pricing.ClaimID {
+		return pricing, errtrace.Errorf("pricing is for claim %q, expected %q", pricing.ClaimID, claim.ClaimID)
+	}
+	services := make(map[string]mph.Service, len(claim.Services))
+	for _, s := range claim.Services {
+		services[s.LineNumber] = s
+	}
+	for _, s := range pricing.Services {
+		if _, ok := services[s.LineNumber]; !ok {
+			return pricing, errtrace.Errorf("claim %q has no service with line number %q", claim.ClaimID, s.LineNumber)
+		}
+	}
+
+	result := pricing
+	result.Services = append([]mph.PricedService(nil), pricing.Services...)
+	result.NetworkCode = c.NetworkCode
+	result.AllowedRepricingCode = c.RepricingCode
+	if result.AllowedRepricingCode == "" {
+		result.AllowedRepricingCode = mph.ClaimRepricingCodeContractPricing
+	}
+
+	for _, rule := range c.Rules {
+		if rule.scope() == ScopeClaim && rule.matchesClaim(claim, pricing) {
+			c.priceClaim(&result, claim, services, rule)
+			return result, nil
+		}
+	}
+	c.priceLines(&result, claim, services)
+	return result, nil
+}
+
+func (c Contract) priceClaim(result *mph.Pricing, claim mph.Claim, services map[string]mph.Service, rule Rule) {
+	a := c.evaluate(rule, amounts{medicare: result.MedicareAmount, billed: claim.BilledAmount, units: 1, days: lengthOfStay(claim)}, "")
+	result.AllowedAmount = a.amount
+	result.AllowedRepricingNote = a.note
+
+	// allocate the claim amount to the lines by billed amount, falling back to the Medicare amount
+	weights := make([]float64, len(result.Services))
+	var total float64
+	for i, s := range result.Services {
+		weights[i] = services[s.LineNumber].BilledAmount
+		total += weights[i]
+	}
+	if total == 0 {
+		for i, s := range result.Services {
+			weights[i] = s.MedicareAmount
+			total += weights[i]
+		}
+	}
+	remaining, last := a.amount, -1
+	for i := range result.Services {
+		s := &result.Services[i]
+		s.AllowedAmount = 0
+		s.AllowedRepricingCode = a.code
+		s.AllowedRepricingFormula = a.formula
+		s.AllowedRepricingNote = "allocated from claim: " + a.note
+		if total > 0 && weights[i] > 0 {
+			s.AllowedAmount = roundCents(a.amount * weights[i] / total)
+			remaining -= s.AllowedAmount
+			last = i
+		}
+	}
+	if last == -1 && len(result.Services) > 0 {
+		last = 0
+	}
+	if last >= 0 {
+		result.Services[last].AllowedAmount = roundCents(result.Services[last].AllowedAmount + remaining)
+	}
+}
+
+func (c Contract) priceLines(result *mph.Pricing, claim mph.Claim, services map[string]mph.Service) {
+	var total float64
+	for i := range result.Services {
+		s := &result.Services[i]
+		service := services[s.LineNumber]
+		rule, ok := c.lineRule(claim, *result, service)
+		if !ok {
+			s.AllowedAmount = 0
+			s.AllowedRepricingCode = mph.LineRepricingCodeNotRepricedPerRequest
+			s.AllowedRepricingFormula = mph.AllowedRepricingFormula{}
+			s.AllowedRepricingNote = fmt.Sprintf("no rule in contract %s matched the service", c.Name)
+			continue
+		}
+		a := c.evaluate(rule, amounts{medicare: s.MedicareAmount, billed: service.BilledAmount, units: units(service), days: units(service)}, service.ProcedureCode)
+		if a.code == mph.LineRepricingCodeMedicarePercent && s.MedicareAmount == 0 {
+			if _, ok := zeroDollarCodes[s.MedicareRepricingCode]; ok {
+				a.code = s.MedicareRepricingCode
+			}
+		}
+		s.AllowedAmount = a.amount
+		s.AllowedRepricingCode = a.code
+		s.AllowedRepricingFormula = a.formula
+		s.AllowedRepricingNote = a.note
+		total += a.amount
+	}
+	result.AllowedAmount = roundCents(total)
+	result.AllowedRepricingNote = "priced per contract " + c.Name
+}
+
+// lineRule returns the first line scope rule which matches the claim and service.
+func (c Contract) lineRule(claim mph.Claim, pricing mph.Pricing, service mph.Service) (Rule, bool) {
+	for _, rule := range c.Rules {
+		if rule.scope() == ScopeLine && rule.matchesClaim(claim, pricing) && rule.matchesLine(service) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// evaluate calculates the allowed amount for a rule. When the formula contains more than one component, the
+// lesser of the components is used (e.g. 180% of Medicare with a cap of 60% of billed).
+func (c Contract) evaluate(rule Rule, in amounts, procedureCode string) allowed {
+	formula := rule.Formula
+	if fee, ok := rule.FeeSchedule[procedureCode]; ok {
+		formula.FeeSchedule = fee
+	}
+
+	var candidates []allowed
+	if formula.MedicarePercent > 0 {
+		candidates = append(candidates, allowed{amount: in.medicare * formula.MedicarePercent / 100, code: mph.LineRepricingCodeMedicarePercent, note: formatPercent(formula.MedicarePercent) + " of Medicare"})
+	}
+	if formula.BilledPercent > 0 {
+		candidates = append(candidates, allowed{amount: in.billed * formula.BilledPercent / 100, code: mph.LineRepricingCodeBilledPercent, note: formatPercent(formula.BilledPercent) + " of billed"})
+	}
+	if formula.FeeSchedule > 0 {
+		candidates = append(candidates, allowed{amount: formula.FeeSchedule * in.units, code: mph.LineRepricingCodeFeeSchedule, note: fmt.Sprintf("fee schedule of %.2f per unit", formula.FeeSchedule)})
+	}
+	if formula.FixedAmount > 0 {
+		candidates = append(candidates, allowed{amount: formula.FixedAmount, code: mph.LineRepricingCodeFlatRate, note: fmt.Sprintf("flat rate of %.2f", formula.FixedAmount)})
+	}
+	if formula.PerDiem > 0 {
+		candidates = append(candidates, allowed{amount: formula.PerDiem * in.days, code: mph.LineRepricingCodePerDiem, note: fmt.Sprintf("per diem of %.2f for %g days", formula.PerDiem, in.days)})
+	}
+
+	var result allowed
+	for i, candidate := range candidates {
+		if i == 0 || candidate.amount < result.amount {
+			result = candidate
+		}
+	}
+	result.amount = roundCents(result.amount)
+	result.formula = formula
+	if len(candidates) == 0 {
+		result.code = mph.LineRepricingCodeNeedsMoreInfo
+		result.note = "no fee schedule amount for procedure code " + procedureCode
+	}
+
+	// a missing billed amount is not a limit, so it does not turn the contract amount into zero
+	if c.LimitToBilled && in.billed > 0 && result.amount > in.billed {
+		result.amount = roundCents(in.billed)
+		result.code = mph.LineRepricingCodeLimitedToBilled
+		result.note += ", limited to billed"
+	}
+
+	var note strings.Builder
+	note.WriteString(c.Name)
+	if rule.Name != "" {
+		note.WriteString(" " + rule.Name)
+	}
+	note.WriteString(": " + result.note)
+	result.note = note.String()
+	return result
+}
+
+// lengthOfStay returns the number of days used for claim-level per diem rates. Same day stays count as one day.
+func lengthOfStay(claim mph.Claim) float64 {
+	return float64(max(1, claim.DateRange().Nights()))
+}
+
+func units(service mph.Service) float64 {
+	if service.Quantity <= 0 {
+		return 1
+	}
+	return service.Quantity
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func formatPercent(percent float64) string {
+	return fmt.Sprintf("%g%%", percent)
+}
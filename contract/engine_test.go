@@ -0,0 +1,163 @@
+package contract
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var outpatientClaim = mph.Claim{
+	ClaimID:       "1",
+	Provider:      mph.Provider{NPI: "123"},
+	FormType:      mph.UBFormType,
+	BillTypeOrPOS: "131",
+	BilledAmount:  1000,
+	Services: []mph.Service{
+		{LineNumber: "1", RevCode: "0450", ProcedureCode: "99284", BilledAmount: 600, Quantity: 1},
+		{LineNumber: "2", RevCode: "0300", ProcedureCode: "80053", BilledAmount: 100, Quantity: 1},
+		{LineNumber: "3", RevCode: "0250", BilledAmount: 50, Quantity: 1},
+		{LineNumber: "4", RevCode: "0510", ProcedureCode: "99213", BilledAmount: 250, Quantity: 2},
+	},
+}
+
+var outpatientPricing = mph.Pricing{
+	ClaimID:        "1",
+	MedicareAmount: 400,
+	Services: []mph.PricedService{
+		{LineNumber: "1", MedicareAmount: 350, MedicareRepricingCode: mph.LineRepricingCodeMedicare},
+		{LineNumber: "2", MedicareAmount: 10, MedicareRepricingCode: mph.LineRepricingCodeMedicare},
+		{LineNumber: "3", MedicareAmount: 0, MedicareRepricingCode: mph.LineRepricingCodePackaged},
+		{LineNumber: "4", MedicareAmount: 40, MedicareRepricingCode: mph.LineRepricingCodeMedicare},
+	},
+}
+
+func TestPriceLines(t *testing.T) {
+	t.Parallel()
+	c := Contract{
+		Name:          "Acme",
+		NetworkCode:   "ACME",
+		LimitToBilled: true,
+		Rules: []Rule{
+			{Name: "ER", RevenueCodes: []CodeRange{{From: "0450", Through: "0459"}}, Formula: mph.AllowedRepricingFormula{MedicarePercent: 200, BilledPercent: 80}},
+			{Name: "Office", FeeSchedule: map[string]float64{"99213": 90}},
+			{Name: "Default", BillTypes: []string{"13"}, Formula: mph.AllowedRepricingFormula{MedicarePercent: 150}},
+		},
+	}
+	result, err := c.Price(outpatientClaim, outpatientPricing)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ACME", result.NetworkCode)
+	assert.Equal(t, mph.ClaimRepricingCodeContractPricing, result.AllowedRepricingCode)
+	assert.Equal(t, "priced per contract Acme", result.AllowedRepricingNote)
+	assert.InDelta(t, 480+15+0+180, result.AllowedAmount, 0.001)
+
+	er := result.Services[0]
+	assert.InDelta(t, 480, er.AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeBilledPercent, er.AllowedRepricingCode)
+	assert.Equal(t, mph.AllowedRepricingFormula{MedicarePercent: 200, BilledPercent: 80}, er.AllowedRepricingFormula)
+	assert.Equal(t, "Acme ER: 80% of billed", er.AllowedRepricingNote)
+
+	lab := result.Services[1]
+	assert.InDelta(t, 15, lab.AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeMedicarePercent, lab.AllowedRepricingCode)
+
+	packaged := result.Services[2]
+	assert.Zero(t, packaged.AllowedAmount)
+	assert.Equal(t, mph.LineRepricingCodePackaged, packaged.AllowedRepricingCode)
+
+	office := result.Services[3]
+	assert.InDelta(t, 180, office.AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeFeeSchedule, office.AllowedRepricingCode)
+	assert.Equal(t, mph.AllowedRepricingFormula{FeeSchedule: 90}, office.AllowedRepricingFormula)
+
+	// the input is not modified
+	assert.Zero(t, outpatientPricing.Services[0].AllowedAmount)
+}
+
+func TestPriceLimitedToBilled(t *testing.T) {
+	t.Parallel()
+	c := Contract{Name: "Acme", LimitToBilled: true, Rules: []Rule{{Formula: mph.AllowedRepricingFormula{MedicarePercent: 2000}}}}
+	result, err := c.Price(outpatientClaim, outpatientPricing)
+	require.NoError(t, err)
+	assert.InDelta(t, 600, result.Services[0].AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeLimitedToBilled, result.Services[0].AllowedRepricingCode)
+	assert.Equal(t, "Acme: 2000% of Medicare, limited to billed", result.Services[0].AllowedRepricingNote)
+
+	// lines without a billed amount are not limited
+	unbilled := outpatientClaim
+	unbilled.Services = slices.Clone(outpatientClaim.Services)
+	unbilled.Services[0].BilledAmount = 0
+	result, err = c.Price(unbilled, outpatientPricing)
+	require.NoError(t, err)
+	assert.InDelta(t, 7000, result.Services[0].AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeMedicarePercent, result.Services[0].AllowedRepricingCode)
+
+	c.LimitToBilled = false
+	result, err = c.Price(outpatientClaim, outpatientPricing)
+	require.NoError(t, err)
+	assert.InDelta(t, 7000, result.Services[0].AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeMedicarePercent, result.Services[0].AllowedRepricingCode)
+}
+
+func TestPriceUnmatchedLine(t *testing.T) {
+	t.Parallel()
+	c := Contract{Name: "Acme", RepricingCode: mph.ClaimRepricingCodeRBPPricing, Rules: []Rule{{ProcedureCodes: []CodeRange{{From: "99284"}}, Formula: mph.AllowedRepricingFormula{FixedAmount: 500}}}}
+	result, err := c.Price(outpatientClaim, outpatientPricing)
+	require.NoError(t, err)
+	assert.Equal(t, mph.ClaimRepricingCodeRBPPricing, result.AllowedRepricingCode)
+	assert.InDelta(t, 500, result.AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodeFlatRate, result.Services[0].AllowedRepricingCode)
+	assert.Equal(t, mph.LineRepricingCodeNotRepricedPerRequest, result.Services[1].AllowedRepricingCode)
+	assert.Equal(t, "no rule in contract Acme matched the service", result.Services[1].AllowedRepricingNote)
+}
+
+func TestPriceClaimScope(t *testing.T) {
+	t.Parallel()
+	claim := mph.Claim{
+		ClaimID:       "2",
+		FormType:      mph.UBFormType,
+		BillTypeOrPOS: "111",
+		BilledAmount:  30000,
+		DateFrom:      mph.NewDate(2024, 3, 1),
+		DateThrough:   mph.NewDate(2024, 3, 4),
+		Services: []mph.Service{
+			{LineNumber: "1", RevCode: "0120", BilledAmount: 10000, Quantity: 3},
+			{LineNumber: "2", RevCode: "0360", BilledAmount: 20000, Quantity: 1},
+		},
+	}
+	pricing := mph.Pricing{
+		ClaimID:              "2",
+		MedicareAmount:       9000,
+		InpatientPriceDetail: mph.InpatientPriceDetail{DRG: "470"},
+		Services:             []mph.PricedService{{LineNumber: "1"}, {LineNumber: "2"}},
+	}
+	c := Contract{Name: "Acme", Rules: []Rule{
+		{Name: "Cardiac", Scope: ScopeClaim, DRGs: []CodeRange{{From: "215", Through: "316"}}, Formula: mph.AllowedRepricingFormula{MedicarePercent: 300}},
+		{Name: "Inpatient", Scope: ScopeClaim, BillTypes: []string{"11"}, Formula: mph.AllowedRepricingFormula{PerDiem: 2500.005}},
+	}}
+	result, err := c.Price(claim, pricing)
+	require.NoError(t, err)
+	assert.InDelta(t, 7500.02, result.AllowedAmount, 0.001)
+	assert.Equal(t, "Acme Inpatient: per diem of 2500.01 for 3 days", result.AllowedRepricingNote)
+	assert.InDelta(t, 2500.01, result.Services[0].AllowedAmount, 0.001)
+	assert.InDelta(t, 5000.01, result.Services[1].AllowedAmount, 0.001)
+	assert.Equal(t, mph.LineRepricingCodePerDiem, result.Services[1].AllowedRepricingCode)
+	assert.Equal(t, "allocated from claim: Acme Inpatient: per diem of 2500.01 for 3 days", result.Services[1].AllowedRepricingNote)
+}
+
+func TestPriceErrors(t *testing.T) {
+	t.Parallel()
+	c := Contract{Name: "Acme", Rules: []Rule{{Formula: mph.AllowedRepricingFormula{MedicarePercent: 150}}}}
+
+	_, err := Contract{}.Price(outpatientClaim, outpatientPricing)
+	require.Error(t, err)
+
+	_, err = c.Price(mph.Claim{ClaimID: "other"}, outpatientPricing)
+	require.Error(t, err)
+
+	_, err = c.Price(mph.Claim{ClaimID: "1"}, outpatientPricing)
+	require.Error(t, err)
+}
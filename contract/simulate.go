@@ -0,0 +1,143 @@
+package contract
+
+import (
+	"cmp"
+	"slices"
+
+	"braces.dev/errtrace"
+	"github.com/mypricehealth/mphgo/analytics"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// Scenario is an alternative set of contract terms to simulate.
+type Scenario struct {
+	Name     string   `json:"name"`
+	Contract Contract `json:"contract"`
+}
+
+// Simulation replays stored pricing results through alternative contract terms without calling the pricing API.
+type Simulation struct {
+	Baseline  *Contract  `json:"baseline,omitzero"` // Contract for the current terms. When nil, the allowed amounts in the stored pricing results are used
+	Scenarios []Scenario `json:"scenarios"`         // Alternative contract terms to compare against the baseline
+	NPIs      []string   `json:"npis,omitzero"`     // When set, only claims for these billing providers are simulated
+}
+
+// ClaimOutcome compares the allowed amount of a single claim under a scenario to the baseline.
+type ClaimOutcome struct {
+	ClaimID         string   `json:"claimID"`
+	MedicareAmount  float64  `json:"medicareAmount"`
+	BaselineAllowed float64  `json:"baselineAllowed"`
+	Allowed         float64  `json:"allowed"`
+	Delta           float64  `json:"delta"`
+	Percent         *float64 `json:"percent,omitzero"` // Percent change from the baseline (nil when the baseline is zero)
+}
+
+// ScenarioResult summarizes the outcome of a scenario.
+type ScenarioResult struct {
+	Name                     string                 `json:"name"`
+	AllowedAmount            float64                `json:"allowedAmount"`            // Total allowed amount under the scenario
+	Delta                    float64                `json:"delta"`                    // Change in the total allowed amount from the baseline
+	Percent                  *float64               `json:"percent,omitzero"`         // Percent change in the total allowed amount from the baseline
+	PercentOfMedicare        float64                `json:"percentOfMedicare"`        // Total allowed amount as a percent of the total Medicare amount
+	PercentOfMedicareByClaim analytics.Distribution `json:"percentOfMedicareByClaim"` // Distribution of each claim's allowed amount as a percent of Medicare
+	DeltaByClaim             analytics.Distribution `json:"deltaByClaim"`             // Distribution of the change in each claim's allowed amount
+	Unchanged                int                    `json:"unchanged"`                // Number of claims whose allowed amount did not change
+	Winners                  []ClaimOutcome         `json:"winners,omitzero"`         // Claims whose allowed amount increased, largest increase first
+	Losers                   []ClaimOutcome         `json:"losers,omitzero"`          // Claims whose allowed amount decreased, largest decrease first
+}
+
+// SimulationReport contains the baseline and the outcome of each scenario.
+type SimulationReport struct {
+	Claims                    int                    `json:"claims"`                    // Number of claims simulated
+	Skipped                   int                    `json:"skipped"`                   // Number of claims skipped because pricing failed or they were for other providers
+	BilledAmount              float64                `json:"billedAmount"`              // Total billed amount of the simulated claims
+	MedicareAmount            float64                `json:"medicareAmount"`            // Total Medicare amount of the simulated claims
+	BaselineAllowed           float64                `json:"baselineAllowed"`           // Total baseline allowed amount
+	BaselinePercentOfMedicare analytics.Distribution `json:"baselinePercentOfMedicare"` // Distribution of each claim's baseline allowed amount as a percent of Medicare
+	Scenarios                 []ScenarioResult       `json:"scenarios"`
+}
+
+// Run simulates each scenario against stored pricing results. Results must be in the same order as the claims.
+func (s Simulation) Run(claims []mph.Claim, results []mph.ErrorAndResult[mph.Pricing]) (SimulationReport, error) {
+	if len(claims) != len(results) {
+		return SimulationReport{}, errtrace.Errorf("got %d claims but %d pricing results", len(claims), len(results))
+	}
+	if len(s.Scenarios) == 0 {
+		return SimulationReport{}, errtrace.Errorf("no scenarios to simulate")
+	}
+
+	var report SimulationReport
+	var baselines []float64
+	var baselinePercents []float64
+	var simulated []int
+	for i, claim := range claims {
+		result := results[i]
+		if result.Error != nil || len(s.NPIs) > 0 && !slices.Contains(s.NPIs, claim.NPI) {
+			report.Skipped++
+			continue
+		}
+		baseline := result.Result.AllowedAmount
+		if s.Baseline != nil {
+			priced, err := s.Baseline.Price(claim, result.Result)
+			if err != nil {
+				return SimulationReport{}, errtrace.Errorf("baseline: %w", err)
+			}
+			baseline = priced.AllowedAmount
+		}
+		report.Claims++
+		report.BilledAmount += claim.BilledAmount
+		report.MedicareAmount += result.Result.MedicareAmount
+		report.BaselineAllowed += baseline
+		baselines = append(baselines, baseline)
+		if result.Result.MedicareAmount > 0 {
+			baselinePercents = append(baselinePercents, baseline/result.Result.MedicareAmount*100)
+		}
+		simulated = append(simulated, i)
+	}
+	report.BaselineAllowed = roundCents(report.BaselineAllowed)
+	report.BaselinePercentOfMedicare = analytics.NewDistribution(baselinePercents)
+
+	for _, scenario := range s.Scenarios {
+		scenarioResult := ScenarioResult{Name: scenario.Name}
+		var percents, deltas []float64
+		for j, i := range simulated {
+			priced, err := scenario.Contract.Price(claims[i], results[i].Result)
+			if err != nil {
+				return SimulationReport{}, errtrace.Errorf("scenario %q: %w", scenario.Name, err)
+			}
+			outcome := ClaimOutcome{
+				ClaimID:         claims[i].ClaimID,
+				MedicareAmount:  priced.MedicareAmount,
+				BaselineAllowed: baselines[j],
+				Allowed:         priced.AllowedAmount,
+				Delta:           roundCents(priced.AllowedAmount - baselines[j]),
+				Percent:         mph.PercentChange(baselines[j], priced.AllowedAmount),
+			}
+			scenarioResult.AllowedAmount += outcome.Allowed
+			deltas = append(deltas, outcome.Delta)
+			if outcome.MedicareAmount > 0 {
+				percents = append(percents, outcome.Allowed/outcome.MedicareAmount*100)
+			}
+			switch {
+			case outcome.Delta > 0:
+				scenarioResult.Winners = append(scenarioResult.Winners, outcome)
+			case outcome.Delta < 0:
+				scenarioResult.Losers = append(scenarioResult.Losers, outcome)
+			default:
+				scenarioResult.Unchanged++
+			}
+		}
+		scenarioResult.AllowedAmount = roundCents(scenarioResult.AllowedAmount)
+		scenarioResult.Delta = roundCents(scenarioResult.AllowedAmount - report.BaselineAllowed)
+		scenarioResult.Percent = mph.PercentChange(report.BaselineAllowed, scenarioResult.AllowedAmount)
+		if report.MedicareAmount > 0 {
+			scenarioResult.PercentOfMedicare = scenarioResult.AllowedAmount / report.MedicareAmount * 100
+		}
+		scenarioResult.PercentOfMedicareByClaim = analytics.NewDistribution(percents)
+		scenarioResult.DeltaByClaim = analytics.NewDistribution(deltas)
+		slices.SortStableFunc(scenarioResult.Winners, func(a, b ClaimOutcome) int { return cmp.Compare(b.Delta, a.Delta) })
+		slices.SortStableFunc(scenarioResult.Losers, func(a, b ClaimOutcome) int { return cmp.Compare(a.Delta, b.Delta) })
+		report.Scenarios = append(report.Scenarios, scenarioResult)
+	}
+	return report, nil
+}
@@ -0,0 +1,92 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func simulationInput() ([]mph.Claim, []mph.ErrorAndResult[mph.Pricing]) {
+	claims := []mph.Claim{
+		{ClaimID: "1", Provider: mph.Provider{NPI: "123"}, BilledAmount: 1000, Services: []mph.Service{{LineNumber: "1", BilledAmount: 1000}}},
+		{ClaimID: "2", Provider: mph.Provider{NPI: "123"}, BilledAmount: 300, Services: []mph.Service{{LineNumber: "1", BilledAmount: 300}}},
+		{ClaimID: "3", Provider: mph.Provider{NPI: "456"}, BilledAmount: 500, Services: []mph.Service{{LineNumber: "1", BilledAmount: 500}}},
+		{ClaimID: "4", Provider: mph.Provider{NPI: "123"}, BilledAmount: 500},
+	}
+	results := []mph.ErrorAndResult[mph.Pricing]{
+		{Result: mph.Pricing{ClaimID: "1", MedicareAmount: 400, AllowedAmount: 720, Services: []mph.PricedService{{LineNumber: "1", MedicareAmount: 400}}}},
+		{Result: mph.Pricing{ClaimID: "2", MedicareAmount: 200, AllowedAmount: 310, Services: []mph.PricedService{{LineNumber: "1", MedicareAmount: 200}}}},
+		{Result: mph.Pricing{ClaimID: "3", MedicareAmount: 100, AllowedAmount: 180, Services: []mph.PricedService{{LineNumber: "1", MedicareAmount: 100}}}},
+		{Error: &mph.ResponseError{Title: "pricing not available", Detail: "provider not found"}},
+	}
+	return claims, results
+}
+
+func TestSimulationRun(t *testing.T) {
+	t.Parallel()
+	claims, results := simulationInput()
+	simulation := Simulation{
+		NPIs: []string{"123"},
+		Scenarios: []Scenario{
+			{Name: "165% capped at 60% of billed", Contract: Contract{Name: "proposal", Rules: []Rule{{Formula: mph.AllowedRepricingFormula{MedicarePercent: 165, BilledPercent: 60}}}}},
+			{Name: "200%", Contract: Contract{Name: "proposal", Rules: []Rule{{Formula: mph.AllowedRepricingFormula{MedicarePercent: 200}}}}},
+		},
+	}
+	report, err := simulation.Run(claims, results)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Claims)
+	assert.Equal(t, 2, report.Skipped)
+	assert.InDelta(t, 1300, report.BilledAmount, 0.001)
+	assert.InDelta(t, 600, report.MedicareAmount, 0.001)
+	assert.InDelta(t, 1030, report.BaselineAllowed, 0.001)
+	assert.InDelta(t, 167.5, report.BaselinePercentOfMedicare.Median, 0.001)
+	require.Len(t, report.Scenarios, 2)
+
+	capped := report.Scenarios[0]
+	assert.InDelta(t, 600+180, capped.AllowedAmount, 0.001)
+	assert.InDelta(t, -250, capped.Delta, 0.001)
+	require.NotNil(t, capped.Percent)
+	assert.InDelta(t, -24.272, *capped.Percent, 0.001)
+	assert.InDelta(t, 130, capped.PercentOfMedicare, 0.001)
+	assert.Empty(t, capped.Winners)
+	require.Len(t, capped.Losers, 2)
+	assert.Equal(t, "2", capped.Losers[0].ClaimID)
+	assert.InDelta(t, -130, capped.Losers[0].Delta, 0.001)
+	assert.Equal(t, "1", capped.Losers[1].ClaimID)
+
+	higher := report.Scenarios[1]
+	assert.InDelta(t, 1200, higher.AllowedAmount, 0.001)
+	require.Len(t, higher.Winners, 2)
+	assert.Equal(t, "2", higher.Winners[0].ClaimID)
+	assert.InDelta(t, 90, higher.Winners[0].Delta, 0.001)
+	assert.Equal(t, 2, higher.DeltaByClaim.Count)
+}
+
+func TestSimulationRunBaselineContract(t *testing.T) {
+	t.Parallel()
+	claims, results := simulationInput()
+	current := Contract{Name: "current", Rules: []Rule{{Formula: mph.AllowedRepricingFormula{MedicarePercent: 150}}}}
+	simulation := Simulation{Baseline: &current, Scenarios: []Scenario{{Name: "same", Contract: current}}}
+	report, err := simulation.Run(claims, results)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Claims)
+	assert.InDelta(t, 1050, report.BaselineAllowed, 0.001)
+	assert.Equal(t, 3, report.Scenarios[0].Unchanged)
+	assert.Zero(t, report.Scenarios[0].Delta)
+}
+
+func TestSimulationRunErrors(t *testing.T) {
+	t.Parallel()
+	claims, results := simulationInput()
+	_, err := Simulation{}.Run(claims, results)
+	require.Error(t, err)
+
+	_, err = Simulation{Scenarios: []Scenario{{Name: "invalid"}}}.Run(claims, results)
+	require.Error(t, err)
+
+	_, err = Simulation{Scenarios: []Scenario{{Name: "invalid"}}}.Run(claims[:1], results)
+	require.Error(t, err)
+}
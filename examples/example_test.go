@@ -2,7 +2,6 @@ package examples
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
@@ -49,12 +48,12 @@ func TestClientWithJSON(t *testing.T) {
 
 func readJSON(filename string) (mph.Claim, error) {
 	var c mph.Claim
-	f, err := os.Open(filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return c, errtrace.Wrap(err)
 	}
 
-	err = json.NewDecoder(f).Decode(&c)
+	err = mph.Unmarshal(data, &c)
 	return c, errtrace.Wrap(err)
 }
 
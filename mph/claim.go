@@ -1,6 +1,13 @@
 package mph
 
-import "github.com/mypricehealth/decimal"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/mypricehealth/decimal"
+)
 
 type FormType string         // Type of form used to submit the claim. Can be HCFA or UB-04
 type BillTypeSequence string // The location where the claim is at in its billing lifecycle (e.g. 0: Non-Pay, 1: Admit Through Discharge, 7: Replacement, etc.)
@@ -143,3 +150,14 @@ type RateSheetService struct {
 	BilledAmount       float64  `json:"billedAmount,omitzero"`       // Billed charge for the service (from SV102 / SV203)
 	AllowedAmount      float64  `json:"allowedAmount,omitzero"`      // Plan allowed amount for the service (non-EDI)
 }
+
+// Fingerprint returns a SHA-256 hash of the claim's JSON encoding. It identifies the exact input used for pricing
+// so that results can be traced back to it without storing the claim itself.
+func (c Claim) Fingerprint() (string, error) {
+	data, err := json.Marshal(c, json.Deterministic(true))
+	if err != nil {
+		return "", errtrace.Wrap(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -1,12 +1,17 @@
 package mph
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
 	"github.com/mypricehealth/sling"
 )
 
@@ -39,7 +44,12 @@ type PriceConfig struct {
 
 // Client is used to interact with the My Price Health API.
 type Client struct {
-	sling *sling.Sling
+	sling        *sling.Sling
+	interceptors []Interceptor
+	tracer       Tracer
+	meter        Meter
+	logger       *slog.Logger
+	verbose      bool
 }
 
 var _ Pricer = &Client{}
@@ -51,7 +61,7 @@ func NewClient(doer sling.Doer, isTest bool, apiKey string) *Client {
 	if isTest {
 		url = "https://api-test.myprice.health"
 	}
-	client := &Client{sling.New().Doer(doer).Base(url).Set("x-api-key", apiKey)}
+	client := &Client{sling: sling.New().Doer(doer).Base(url).Set("x-api-key", apiKey).ResponseDecoder(responseDecoder{opts: decodeOptions()})}
 	return client
 }
 
@@ -60,45 +70,103 @@ func NewDefaultClient(apiKey string) *Client {
 	return NewClient(http.DefaultClient, false, apiKey)
 }
 
-func (c Client) receiveResponse(ctx context.Context, s *sling.Sling, path string) Response[Pricing] {
-	var response Response[Pricing]
-	res, err := s.Path(path).ReceiveWithContext(ctx, &response, &response)
-	if err != nil {
-		response.Error = &ResponseError{Title: fmt.Sprintf("fatal error calling %s", path), Detail: err.Error()}
-		response.StatusCode = res.StatusCode
-	}
-	return response
+// envelope is implemented by the response types returned by the Client.
+type envelope interface {
+	setError(err *ResponseError, statusCode, count int)
 }
 
-func (c *Client) receiveResponses(ctx context.Context, s *sling.Sling, path string, count int) ErrorAndResultResponses[Pricing] {
-	var responses ErrorAndResultResponses[Pricing]
-	res, err := s.Path(path).ReceiveWithContext(ctx, &responses, &responses)
+func (r *Response[Result]) setError(err *ResponseError, statusCode, count int) {
+	r.Error = err
+	r.StatusCode = statusCode
+}
+
+func (r *ErrorAndResultResponses[Result]) setError(err *ResponseError, statusCode, count int) {
+	r.Error = err
+	r.ErrorCount = count
+	r.StatusCode = statusCode
+}
+
+// do sends a request through the interceptors and decodes the response into response.
+func (c *Client) do(ctx context.Context, call *Call, response envelope) {
+	ctx, span := c.startSpan(ctx, call)
+	defer span.End()
+
+	var buf bytes.Buffer
+	err := json.MarshalEncode(jsontext.NewEncoder(&buf), call.Input)
 	if err != nil {
-		responses.Error = &ResponseError{Title: fmt.Sprintf("fatal error calling %s", path), Detail: err.Error()}
-		responses.ErrorCount = count
-		responses.StatusCode = res.StatusCode
-	}
-	return responses
+		response.setError(&ResponseError{Title: fmt.Sprintf("unable to encode request to %s", call.Path), Detail: err.Error()}, 0, call.Count)
+		c.finish(ctx, span, call, Outcome{Response: response, Err: err})
+		return
+	}
+	call.Body = buf.Bytes()
+	c.logStart(ctx, call)
+
+	var statusCode int
+	before := 0
+	for _, interceptor := range c.interceptors {
+		var next context.Context
+		if next, err = interceptor.Before(ctx, call); err != nil {
+			problem := interceptorError(call.Path, err)
+			response.setError(problem, problem.Status, call.Count)
+			break
+		}
+		ctx = next
+		before++
+	}
+
+	start := time.Now()
+	if err == nil {
+		var res *http.Response
+		s := c.sling.New().Body(bytes.NewReader(call.Body)).Set("Content-Type", "application/json").AddHeaders(call.Header).Method("POST").Path(call.Path)
+		res, err = s.ReceiveWithContext(ctx, response, response)
+		if res != nil {
+			statusCode = res.StatusCode
+		}
+		if err != nil {
+			response.setError(&ResponseError{Title: fmt.Sprintf("fatal error calling %s", call.Path), Detail: err.Error()}, statusCode, call.Count)
+		}
+	}
+
+	outcome := Outcome{StatusCode: statusCode, Duration: time.Since(start), Response: response, Err: err}
+	for i := before - 1; i >= 0; i-- {
+		c.interceptors[i].After(ctx, call, outcome)
+	}
+	c.finish(ctx, span, call, outcome)
+}
+
+// finish records the outcome of a call using the Tracer, Meter and logger.
+func (c *Client) finish(ctx context.Context, span Span, call *Call, outcome Outcome) {
+	summary := summarize(call, outcome)
+	c.observe(ctx, span, call, outcome, summary)
+	c.logOutcome(ctx, call, outcome, summary)
 }
 
 // EstimateRateSheet is used to get the estimated Medicare reimbursement of a single claim.
 func (c *Client) EstimateRateSheet(ctx context.Context, inputs ...RateSheet) ErrorAndResultResponses[Pricing] {
-	return c.receiveResponses(ctx, c.sling.BodyJSON(inputs).Method("POST"), "/v1/medicare/estimate/rate-sheet", len(inputs))
+	var responses ErrorAndResultResponses[Pricing]
+	c.do(ctx, &Call{Path: "/v1/medicare/estimate/rate-sheet", Input: inputs, Count: len(inputs), Header: http.Header{}}, &responses)
+	return responses
 }
 
 // EstimateClaims is used to get the estimated Medicare reimbursement of multiple claims.
 func (c *Client) EstimateClaims(ctx context.Context, inputs ...Claim) ErrorAndResultResponses[Pricing] {
-	return c.receiveResponses(ctx, c.sling.BodyJSON(inputs).Method("POST"), "/v1/medicare/estimate/claims", len(inputs))
+	var responses ErrorAndResultResponses[Pricing]
+	c.do(ctx, &Call{Path: "/v1/medicare/estimate/claims", Input: inputs, Count: len(inputs), Header: http.Header{}}, &responses)
+	return responses
 }
 
 // Price is used to get the Medicare reimbursement of a single claim.
 func (c *Client) Price(ctx context.Context, config PriceConfig, input Claim) Response[Pricing] {
-	return c.receiveResponse(ctx, c.sling.BodyJSON(input).AddHeaders(GetHeaders(config)).Method("POST"), "/v1/medicare/price/claim")
+	var response Response[Pricing]
+	c.do(ctx, &Call{Path: "/v1/medicare/price/claim", Config: config, Input: input, Count: 1, Header: GetHeaders(config)}, &response)
+	return response
 }
 
 // PriceBatch is used to get the Medicare reimbursement of multiple claims.
 func (c *Client) PriceBatch(ctx context.Context, config PriceConfig, inputs ...Claim) ErrorAndResultResponses[Pricing] {
-	return c.receiveResponses(ctx, c.sling.BodyJSON(inputs).AddHeaders(GetHeaders(config)).Method("POST"), "/v1/medicare/price/claims", len(inputs))
+	var responses ErrorAndResultResponses[Pricing]
+	c.do(ctx, &Call{Path: "/v1/medicare/price/claims", Config: config, Input: inputs, Count: len(inputs), Header: GetHeaders(config)}, &responses)
+	return responses
 }
 
 func GetHeaders(config PriceConfig) http.Header {
@@ -166,6 +234,7 @@ func ParseHeaders(r *http.Request) (PriceConfig, error) {
 		ContinueOnProviderMatchFail:               r.Header.Get("continue-on-provider-match-fail") == "true",
 		AssumeImpossibleAnesthesiaUnitsAreMinutes: r.Header.Get("assume-impossible-anesthesia-units-are-minutes") == "true",
 		FallbackToMaxAnesthesiaUnitsPerDay:        r.Header.Get("fallback-to-max-anesthesia-units-per-day") == "true",
+		DisableMachineLearningEstimates:           r.Header.Get("disable-machine-learning-estimates") == "true",
 		AllowPartialResults:                       r.Header.Get("allow-partial-results") == "true",
 		ContractRuleset:                           r.Header.Get("contract-ruleset"),
 	}
@@ -197,4 +197,9 @@ func TestParseHeaders(t *testing.T) {
 	config, err := ParseHeaders(input)
 	require.NoError(t, err)
 	assert.Equal(t, expected, config)
+
+	expected = PriceConfig{DisableMachineLearningEstimates: true, FallbackToMaxAnesthesiaUnitsPerDay: true, ContractRuleset: "acme"}
+	config, err = ParseHeaders(&http.Request{Header: GetHeaders(expected)})
+	require.NoError(t, err)
+	assert.Equal(t, expected, config)
 }
@@ -1,10 +1,12 @@
 package mph
 
 import (
-	"encoding/json"
+	"encoding"
+	"strings"
 	"time"
 
 	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
 )
 
 // Date is a custom type for representing dates in the format YYYYMMDD
@@ -14,8 +16,13 @@ type Date struct {
 
 var _ json.Marshaler = &Date{}
 var _ json.Unmarshaler = &Date{}
+var _ encoding.TextMarshaler = &Date{}
+var _ encoding.TextUnmarshaler = &Date{}
 
-const dateFormat = "20060102"
+const (
+	dateFormat    = "20060102"
+	isoDateFormat = "2006-01-02"
+)
 
 // NewDate is used to create a new Date object
 func NewDate(year, month, day int) Date {
@@ -27,6 +34,81 @@ func NewDatePtr(year, month, day int) *Date {
 	return &Date{time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)}
 }
 
+// ParseDate parses a date in CCYYMMDD, ISO 8601 (CCYY-MM-DD) or RFC 3339 format. The time of day of RFC 3339
+// timestamps is ignored. An empty string is parsed as the zero Date.
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return Date{}, nil
+	case len(s) == len(dateFormat):
+		t, err := time.Parse(dateFormat, s)
+		return Date{t}, errtrace.Wrap(err)
+	case len(s) == len(isoDateFormat):
+		t, err := time.Parse(isoDateFormat, s)
+		return Date{t}, errtrace.Wrap(err)
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return Date{}, errtrace.Errorf("invalid date %q: expected CCYYMMDD, CCYY-MM-DD or RFC 3339", s)
+	}
+	return NewDate(t.Year(), int(t.Month()), t.Day()), nil
+}
+
+// IsZero returns true if the date is not set.
+func (d Date) IsZero() bool {
+	return d.Time.IsZero()
+}
+
+// Before returns true if d is on an earlier day than other.
+func (d Date) Before(other Date) bool {
+	return d.day() < other.day()
+}
+
+// After returns true if d is on a later day than other.
+func (d Date) After(other Date) bool {
+	return d.day() > other.day()
+}
+
+// Equal returns true if d and other are on the same day.
+func (d Date) Equal(other Date) bool {
+	return d.day() == other.day()
+}
+
+// AddDays returns the date the given number of days after d. Negative days return an earlier date.
+func (d Date) AddDays(days int) Date {
+	return Date{d.Time.AddDate(0, 0, days)}
+}
+
+// DaysUntil returns the number of days from d to other, which is negative if other is before d.
+func (d Date) DaysUntil(other Date) int {
+	return int(other.day() - d.day())
+}
+
+// AgeOn returns the age in whole years on the date on of a person born on d. Someone born on February 29 turns a
+// year older on March 1 in years which are not leap years.
+func (d Date) AgeOn(on Date) int {
+	age := on.Time.Year() - d.Time.Year()
+	if on.Time.Month() < d.Time.Month() || (on.Time.Month() == d.Time.Month() && on.Time.Day() < d.Time.Day()) {
+		age--
+	}
+	return age
+}
+
+// ISO returns the date in ISO 8601 (CCYY-MM-DD) format or an empty string if the date is not set.
+func (d Date) ISO() string {
+	if d.Time.IsZero() {
+		return ""
+	}
+	return d.Time.Format(isoDateFormat)
+}
+
+// day returns the number of days since the Unix epoch, ignoring the time of day and location.
+func (d Date) day() int64 {
+	year, month, day := d.Time.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+}
+
 func (d Date) String() string {
 	if d.Time.IsZero() {
 		return ""
@@ -52,3 +134,18 @@ func (d *Date) UnmarshalJSON(data []byte) error {
 	*d = Date{t}
 	return errtrace.Wrap(err)
 }
+
+// MarshalText encodes the date in CCYYMMDD format, or as an empty string if the date is not set.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a date in any of the formats accepted by ParseDate.
+func (d *Date) UnmarshalText(data []byte) error {
+	parsed, err := ParseDate(string(data))
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	*d = parsed
+	return nil
+}
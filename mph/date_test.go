@@ -2,8 +2,10 @@ package mph
 
 import (
 	"testing"
+	"testing/quick"
 	"time"
 
+	"github.com/go-json-experiment/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,3 +41,110 @@ func TestUnmarshalJSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, Date{}, d)
 }
+
+func FuzzDateJSON(f *testing.F) {
+	for _, seed := range []string{`"20200101"`, `null`, `""`, `"20201301"`, `"2020-01-01"`, `20200101`, `"00010101"`, `"99991231"`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkCodecs[Date](t, data)
+	})
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	t.Parallel()
+	roundTrip := func(days uint32) bool {
+		d := Date{time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days%3652059))}
+		data, err := d.MarshalJSON()
+		if err != nil {
+			return false
+		}
+		var decoded Date
+		return decoded.UnmarshalJSON(data) == nil && decoded == d
+	}
+	require.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+	for input, expected := range map[string]Date{
+		"20200227":                  NewDate(2020, 2, 27),
+		"2020-02-27":                NewDate(2020, 2, 27),
+		"2020-02-27T23:30:00-05:00": NewDate(2020, 2, 27),
+		" 20200227 ":                NewDate(2020, 2, 27),
+		"":                          {},
+	} {
+		d, err := ParseDate(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, d, input)
+	}
+	for _, input := range []string{"20201301", "2020-02-30", "02/27/2020", "2020"} {
+		_, err := ParseDate(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDateHelpers(t *testing.T) {
+	t.Parallel()
+	d := NewDate(2020, 2, 27)
+	assert.Equal(t, NewDate(2020, 3, 1), d.AddDays(3))
+	assert.Equal(t, NewDate(2019, 12, 31), d.AddDays(-58))
+	assert.True(t, d.Before(d.AddDays(1)))
+	assert.False(t, d.Before(d))
+	assert.True(t, d.After(d.AddDays(-1)))
+	assert.True(t, d.Equal(Date{time.Date(2020, 2, 27, 23, 0, 0, 0, time.FixedZone("EST", -5*60*60))}))
+	assert.Equal(t, 3, d.DaysUntil(NewDate(2020, 3, 1)))
+	assert.Equal(t, -366, d.DaysUntil(NewDate(2019, 2, 26)))
+	assert.Equal(t, "2020-02-27", d.ISO())
+	assert.Empty(t, Date{}.ISO())
+	assert.True(t, Date{}.IsZero())
+
+	dob := NewDate(1988, 6, 15)
+	assert.Equal(t, 31, dob.AgeOn(NewDate(2020, 2, 27)))
+	assert.Equal(t, 32, dob.AgeOn(NewDate(2020, 6, 15)))
+	assert.Equal(t, 31, dob.AgeOn(NewDate(2020, 6, 14)))
+	leap := NewDate(2000, 2, 29)
+	assert.Equal(t, 0, leap.AgeOn(NewDate(2001, 2, 28)))
+	assert.Equal(t, 1, leap.AgeOn(NewDate(2001, 3, 1)))
+}
+
+func TestDateText(t *testing.T) {
+	t.Parallel()
+	data, err := NewDate(2020, 2, 27).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "20200227", string(data))
+	data, err = Date{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	var d Date
+	require.NoError(t, d.UnmarshalText([]byte("2020-02-27")))
+	assert.Equal(t, NewDate(2020, 2, 27), d)
+	assert.Error(t, d.UnmarshalText([]byte("tomorrow")))
+}
+
+func TestDateTextRoundTrip(t *testing.T) {
+	t.Parallel()
+	roundTrip := func(days uint32) bool {
+		d := Date{time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days%3652059))}
+		data, err := d.MarshalText()
+		if err != nil || len(data) != len(dateFormat) || string(data) != d.Time.Format(dateFormat) {
+			return false
+		}
+		var decoded Date
+		return decoded.UnmarshalText(data) == nil && decoded == d
+	}
+	require.NoError(t, quick.Check(roundTrip, nil))
+
+	var zero Date
+	require.NoError(t, zero.UnmarshalText(nil))
+	assert.True(t, zero.IsZero())
+
+	// map keys are encoded as text
+	data, err := json.Marshal(map[Date]int{NewDate(2020, 2, 27): 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"20200227":1}`, string(data))
+	var decoded map[Date]int
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, map[Date]int{NewDate(2020, 2, 27): 1}, decoded)
+}
@@ -0,0 +1,148 @@
+package mph
+
+import (
+	"iter"
+	"strings"
+
+	"braces.dev/errtrace"
+)
+
+// DateRange is an inclusive range of dates such as the dates of service of a claim. A range without a Through date
+// is a single day.
+type DateRange struct {
+	From    Date `json:"from,omitzero"`    // First date in the range
+	Through Date `json:"through,omitzero"` // Last date in the range (defaults to From)
+}
+
+// NewDateRange returns the range of dates from from through through.
+func NewDateRange(from, through Date) DateRange {
+	return DateRange{From: from, Through: through}
+}
+
+// ParseDateRange parses a range in X12 RD8 (CCYYMMDD-CCYYMMDD) or ISO 8601 interval (start/end) format. Each date
+// of an ISO 8601 interval may use any of the formats accepted by ParseDate. A single date is parsed as a range of
+// one day.
+func ParseDateRange(s string) (DateRange, error) {
+	s = strings.TrimSpace(s)
+	var from, through string
+	switch {
+	case strings.Contains(s, "/"):
+		from, through, _ = strings.Cut(s, "/")
+	case len(s) == 2*len(dateFormat)+1 && s[len(dateFormat)] == '-':
+		from, through = s[:len(dateFormat)], s[len(dateFormat)+1:]
+	default:
+		d, err := ParseDate(s)
+		return DateRange{From: d, Through: d}, errtrace.Wrap(err)
+	}
+	var r DateRange
+	var err error
+	if r.From, err = ParseDate(from); err != nil {
+		return DateRange{}, errtrace.Wrap(err)
+	}
+	if r.Through, err = ParseDate(through); err != nil {
+		return DateRange{}, errtrace.Wrap(err)
+	}
+	if r.From.IsZero() || r.Through.IsZero() || r.Through.Before(r.From) {
+		return DateRange{}, errtrace.Errorf("invalid date range %q", s)
+	}
+	return r, nil
+}
+
+// DateRange returns the dates of service of the claim.
+func (c Claim) DateRange() DateRange {
+	return DateRange{From: c.DateFrom, Through: c.DateThrough}
+}
+
+// DateRange returns the dates of the service.
+func (s Service) DateRange() DateRange {
+	return DateRange{From: s.DateFrom, Through: s.DateThrough}
+}
+
+// through returns the last date of the range, which is From when Through is not set.
+func (r DateRange) through() Date {
+	if r.Through.IsZero() {
+		return r.From
+	}
+	return r.Through
+}
+
+// IsZero returns true if the range has no dates.
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero()
+}
+
+// IsValid returns true if the range has a start date which is not after its end date.
+func (r DateRange) IsValid() bool {
+	return !r.From.IsZero() && !r.through().Before(r.From)
+}
+
+// Days returns the number of days in the range, counting both the first and last day. It returns 0 if the range is
+// not valid.
+func (r DateRange) Days() int {
+	if !r.IsValid() {
+		return 0
+	}
+	return r.From.DaysUntil(r.through()) + 1
+}
+
+// Nights returns the number of nights in the range, which is the length of stay for an inpatient admission. It
+// returns 0 if the range is not valid.
+func (r DateRange) Nights() int {
+	if !r.IsValid() {
+		return 0
+	}
+	return r.From.DaysUntil(r.through())
+}
+
+// Contains returns true if d is within the range.
+func (r DateRange) Contains(d Date) bool {
+	return r.IsValid() && !d.IsZero() && !d.Before(r.From) && !d.After(r.through())
+}
+
+// ContainsRange returns true if every date of other is within the range.
+func (r DateRange) ContainsRange(other DateRange) bool {
+	return other.IsValid() && r.Contains(other.From) && r.Contains(other.through())
+}
+
+// Overlaps returns true if the ranges have at least one date in common.
+func (r DateRange) Overlaps(other DateRange) bool {
+	_, ok := r.Intersect(other)
+	return ok
+}
+
+// Intersect returns the dates the ranges have in common. It returns false if they have none.
+func (r DateRange) Intersect(other DateRange) (DateRange, bool) {
+	if !r.IsValid() || !other.IsValid() {
+		return DateRange{}, false
+	}
+	from, through := r.From, r.through()
+	if other.From.After(from) {
+		from = other.From
+	}
+	if other.through().Before(through) {
+		through = other.through()
+	}
+	if through.Before(from) {
+		return DateRange{}, false
+	}
+	return DateRange{From: from, Through: through}, true
+}
+
+// All returns an iterator over each date in the range in order.
+func (r DateRange) All() iter.Seq[Date] {
+	return func(yield func(Date) bool) {
+		for i := range r.Days() {
+			if !yield(r.From.AddDays(i)) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the range in X12 RD8 (CCYYMMDD-CCYYMMDD) format or an empty string if the range has no dates.
+func (r DateRange) String() string {
+	if r.IsZero() {
+		return ""
+	}
+	return r.From.String() + "-" + r.through().String()
+}
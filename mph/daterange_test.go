@@ -0,0 +1,69 @@
+package mph
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateRange(t *testing.T) {
+	t.Parallel()
+	stay := NewDateRange(NewDate(2020, 2, 27), NewDate(2020, 3, 2))
+	for _, input := range []string{"20200227-20200302", "2020-02-27/2020-03-02", "20200227/20200302", " 20200227-20200302 "} {
+		r, err := ParseDateRange(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, stay, r, input)
+	}
+
+	r, err := ParseDateRange("2020-02-27")
+	require.NoError(t, err)
+	assert.Equal(t, NewDateRange(NewDate(2020, 2, 27), NewDate(2020, 2, 27)), r)
+
+	for _, input := range []string{"20200302-20200227", "20200227-", "/20200302", "20200227-2020030", "2020-02-27/tomorrow"} {
+		_, err := ParseDateRange(input)
+		assert.Error(t, err, input)
+	}
+	assert.Equal(t, "20200227-20200302", stay.String())
+	assert.Empty(t, DateRange{}.String())
+}
+
+func TestDateRange(t *testing.T) {
+	t.Parallel()
+	stay := Claim{DateFrom: NewDate(2020, 2, 27), DateThrough: NewDate(2020, 3, 2)}.DateRange()
+	assert.True(t, stay.IsValid())
+	assert.Equal(t, 5, stay.Days())
+	assert.Equal(t, 4, stay.Nights())
+	assert.True(t, stay.Contains(NewDate(2020, 2, 29)))
+	assert.True(t, stay.Contains(NewDate(2020, 3, 2)))
+	assert.False(t, stay.Contains(NewDate(2020, 3, 3)))
+	assert.False(t, stay.Contains(Date{}))
+
+	visit := Service{DateFrom: NewDate(2020, 2, 28)}.DateRange()
+	assert.Equal(t, 1, visit.Days())
+	assert.Equal(t, 0, visit.Nights())
+	assert.Equal(t, "20200228-20200228", visit.String())
+	assert.True(t, stay.ContainsRange(visit))
+	assert.False(t, visit.ContainsRange(stay))
+
+	overlap, ok := stay.Intersect(NewDateRange(NewDate(2020, 3, 1), NewDate(2020, 3, 10)))
+	assert.True(t, ok)
+	assert.Equal(t, NewDateRange(NewDate(2020, 3, 1), NewDate(2020, 3, 2)), overlap)
+	assert.True(t, stay.Overlaps(visit))
+	assert.False(t, stay.Overlaps(NewDateRange(NewDate(2020, 3, 3), NewDate(2020, 3, 10))))
+
+	assert.Equal(t, []Date{NewDate(2020, 2, 27), NewDate(2020, 2, 28), NewDate(2020, 2, 29), NewDate(2020, 3, 1), NewDate(2020, 3, 2)}, slices.Collect(stay.All()))
+	for d := range stay.All() {
+		assert.Equal(t, NewDate(2020, 2, 27), d)
+		break
+	}
+
+	reversed := NewDateRange(NewDate(2020, 3, 2), NewDate(2020, 2, 27))
+	assert.False(t, reversed.IsValid())
+	assert.Equal(t, 0, reversed.Days())
+	assert.Empty(t, slices.Collect(reversed.All()))
+	assert.False(t, reversed.Overlaps(stay))
+	assert.True(t, DateRange{}.IsZero())
+	assert.Equal(t, 0, DateRange{}.Nights())
+}
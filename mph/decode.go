@@ -0,0 +1,57 @@
+package mph
+
+import (
+	"net/http"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/mypricehealth/sling"
+)
+
+// defaultDecodeOptions ignore unknown members and let the last duplicate name win so that new API fields do not
+// break older clients.
+var defaultDecodeOptions = json.JoinOptions(json.RejectUnknownMembers(false), jsontext.AllowDuplicateNames(true))
+
+// StrictDecoding returns options which make decoding claims, responses and the other types in this package fail
+// when an object has members which are not defined by the type, has duplicate names or has unknown codes. Pass them
+// to Unmarshal, Client.SetDecodeOptions or NewHandler in integration tests to detect drift between this package and
+// the API.
+func StrictDecoding() json.Options {
+	return json.JoinOptions(json.RejectUnknownMembers(true), jsontext.AllowDuplicateNames(false))
+}
+
+// decodeOptions returns the default options overridden by opts.
+func decodeOptions(opts ...json.Options) json.Options {
+	return json.JoinOptions(append([]json.Options{defaultDecodeOptions}, opts...)...)
+}
+
+// isStrict reports whether opts reject unknown members, in which case unknown codes are rejected as well.
+func isStrict(opts json.Options) bool {
+	strict, _ := json.GetOption(opts, json.RejectUnknownMembers)
+	return strict
+}
+
+// Unmarshal decodes data into v using the same JSON package and default options as the client and handler. Use it
+// instead of encoding/json to decode claims, passing StrictDecoding to decode them strictly.
+func Unmarshal(data []byte, v any, opts ...json.Options) error {
+	return errtrace.Wrap(json.Unmarshal(data, v, decodeOptions(opts...)))
+}
+
+// responseDecoder decodes API responses using the decode options of a Client.
+type responseDecoder struct {
+	opts json.Options
+}
+
+var _ sling.ResponseDecoder = responseDecoder{}
+
+func (d responseDecoder) Decode(resp *http.Response, v any) error {
+	return errtrace.Wrap(json.UnmarshalRead(resp.Body, v, d.opts))
+}
+
+// SetDecodeOptions sets the options used to decode responses, overriding the defaults which ignore unknown members
+// (e.g. StrictDecoding()). SetDecodeOptions is not safe to call concurrently with requests.
+func (c *Client) SetDecodeOptions(opts ...json.Options) *Client {
+	c.sling.ResponseDecoder(responseDecoder{opts: decodeOptions(opts...)})
+	return c
+}
@@ -0,0 +1,74 @@
+package mph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLenientDecoding(t *testing.T) {
+	t.Parallel()
+	var r Response[Pricing]
+	require.NoError(t, Unmarshal([]byte(`{"result":{"claimID":"1","newField":true},"status":200,"status":201}`), &r))
+	assert.Equal(t, Response[Pricing]{Result: Pricing{ClaimID: "1"}, StatusCode: 201}, r)
+
+	var claim Claim
+	require.NoError(t, Unmarshal([]byte(`{"claimID":"1","newField":true}`), &claim))
+	assert.Equal(t, "1", claim.ClaimID)
+}
+
+func TestStrictDecoding(t *testing.T) {
+	t.Parallel()
+	strict := StrictDecoding()
+
+	var r Response[Pricing]
+	assert.ErrorContains(t, Unmarshal([]byte(`{"result":{"claimID":"1","newField":true},"status":200}`), &r, strict), "newField")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"result":{"claimID":"1"},"status":200,"status":201}`), &r, strict), "duplicate")
+	assert.Error(t, Unmarshal([]byte(`{"result":{"claimID":"1"},"extra":1,"status":200}`), &r, strict))
+
+	var responses ErrorAndResultResponses[Pricing]
+	assert.ErrorContains(t, Unmarshal([]byte(`{"results":[{"claimID":"1","newField":true}],"status":200}`), &responses, strict), "newField")
+	assert.Error(t, Unmarshal([]byte(`{"results":[{"error":{"title":"x","title":"y"}}],"status":200}`), &responses, strict))
+
+	var claim Claim
+	assert.ErrorContains(t, Unmarshal([]byte(`{"claimID":"1","newField":true}`), &claim, strict), "newField")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"claimID":"1","services":[{"lineNumber":"1","newField":true}]}`), &claim, strict), "newField")
+	assert.Error(t, Unmarshal([]byte(`{"claimID":"1","claimID":"2"}`), &claim, strict))
+
+	// problem documents may have extension members
+	require.NoError(t, Unmarshal([]byte(`{"title":"not found","status":404,"requestID":"abc"}`), &r, strict))
+	assert.Equal(t, 404, r.StatusCode)
+	assert.Equal(t, "not found", r.Error.Title)
+	require.NoError(t, Unmarshal([]byte(`{"error":{"title":"not found","requestID":"abc"},"status":404}`), &responses, strict))
+	assert.Equal(t, "not found", responses.Error.Title)
+}
+
+func TestStrictDecodingClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	drifted := handlerDoer{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":{"claimID":"1","newField":true},"status":200}`))
+	})}
+	response := NewClient(drifted, true, "key").Price(ctx, PriceConfig{}, Claim{ClaimID: "1"})
+	require.Nil(t, response.Error, "unknown members are ignored by default")
+	response = NewClient(drifted, true, "key").SetDecodeOptions(StrictDecoding()).Price(ctx, PriceConfig{}, Claim{ClaimID: "1"})
+	require.NotNil(t, response.Error)
+	assert.Contains(t, response.Error.Detail, "newField")
+
+	client := NewClient(handlerDoer{NewHandler(&stubPricer{}, StrictDecoding())}, true, "key").SetDecodeOptions(StrictDecoding())
+	response = client.Price(ctx, PriceConfig{}, Claim{ClaimID: "1", BilledAmount: 100})
+	require.Nil(t, response.Error)
+	assert.Equal(t, 100.0, response.Result.MedicareAmount)
+
+	w := httptest.NewRecorder()
+	NewHandler(&stubPricer{}, StrictDecoding()).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/medicare/price/claim", strings.NewReader(`{"claimID":"1","newField":true}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "newField")
+}
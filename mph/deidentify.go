@@ -0,0 +1,161 @@
+package mph
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"braces.dev/errtrace"
+)
+
+// RestrictedZIP3 contains the 3-digit ZIP code prefixes with a population of 20,000 or fewer (2010 census). Under
+// the HIPAA Safe Harbor method these must be changed to 000.
+var RestrictedZIP3 = map[string]bool{
+	"036": true, "059": true, "102": true, "203": true, "205": true, "369": true, "556": true, "692": true,
+	"753": true, "772": true, "821": true, "823": true, "878": true, "879": true, "884": true, "893": true,
+}
+
+// maxAge is the age at which Safe Harbor requires ages to be aggregated into a single category.
+const maxAge = 90
+
+// DeidentifyOptions configures Claim.Deidentify.
+type DeidentifyOptions struct {
+	Key              []byte // Secret key used to create claim ID pseudonyms and date shifts (required)
+	MaxDateShiftDays int    // Shift service dates by up to this many days in either direction (required unless KeepDates is set)
+	KeepDates        bool   // Leave service dates unchanged instead of shifting them
+	ReferenceDate    Date   // Date at which the patient's age is determined when the claim has no DateFrom
+}
+
+// validate checks that the options are complete. Dates are only kept unshifted when asked for explicitly, since the
+// zero value of MaxDateShiftDays would otherwise leave the exact service dates in de-identified claims.
+func (o DeidentifyOptions) validate() error {
+	switch {
+	case len(o.Key) == 0:
+		return errtrace.Errorf("a key is required to de-identify claims")
+	case o.KeepDates && o.MaxDateShiftDays != 0:
+		return errtrace.Errorf("MaxDateShiftDays cannot be set when KeepDates is set")
+	case !o.KeepDates && o.MaxDateShiftDays <= 0:
+		return errtrace.Errorf("MaxDateShiftDays must be positive unless KeepDates is set")
+	}
+	return nil
+}
+
+// Pseudonym returns a keyed pseudonym for value. The same value and key always produce the same pseudonym, so
+// de-identified claims and their pricing can be matched without revealing the original value.
+func (o DeidentifyOptions) Pseudonym(value string) string {
+	return hex.EncodeToString(o.mac("pseudonym", value)[:16])
+}
+
+// DateShift returns the number of days the dates of the claim with claimID are shifted by.
+func (o DeidentifyOptions) DateShift(claimID string) int {
+	if o.MaxDateShiftDays <= 0 {
+		return 0
+	}
+	n := binary.BigEndian.Uint64(o.mac("date-shift", claimID))
+	return int(n%uint64(2*o.MaxDateShiftDays+1)) - o.MaxDateShiftDays
+}
+
+func (o DeidentifyOptions) mac(purpose, value string) []byte {
+	h := hmac.New(sha256.New, o.Key)
+	h.Write([]byte(purpose))
+	h.Write([]byte{0})
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
+
+// Deidentify returns a date-shifted, de-identified copy of the claim. The HIPAA Safe Harbor identifiers in the model
+// are removed or generalized, except for service dates:
+//   - ClaimID is replaced with a keyed pseudonym
+//   - PatientDateOfBirth is reduced to January 1 of the birth year, and patients 90 or older are aggregated into a
+//     single birth year 90 years before the claim
+//   - AmbulancePickupZIP is reduced to its first 3 digits, or 000 for sparsely populated areas
+//   - Provider phone numbers, fax numbers and email addresses are removed
+//   - Service dates are shifted by the same number of days across the claim and its services, so that lengths of
+//     stay are unchanged
+//
+// Fields used for pricing (codes, amounts and provider identifiers) are preserved. Service dates are needed for
+// pricing, so they are shifted rather than reduced to the year as Safe Harbor requires. The result is a limited data
+// set rather than a Safe Harbor de-identified claim, and sharing it needs a data use agreement or an expert
+// determination.
+//
+// The pseudonym and date shift are derived from the ClaimID, so claims without one are rejected rather than all
+// sharing the same pseudonym. Claims with a PatientDateOfBirth also need a DateFrom, or opts.ReferenceDate, to
+// determine the patient's age.
+func (c Claim) Deidentify(opts DeidentifyOptions) (Claim, error) {
+	if err := opts.validate(); err != nil {
+		return Claim{}, errtrace.Wrap(err)
+	}
+	if c.ClaimID == "" {
+		return Claim{}, errtrace.Errorf("a claim ID is required to de-identify a claim")
+	}
+	shift := opts.DateShift(c.ClaimID)
+
+	if c.PatientDateOfBirth != nil && !c.PatientDateOfBirth.IsZero() {
+		reference := c.DateFrom
+		if reference.IsZero() {
+			reference = opts.ReferenceDate
+		}
+		if reference.IsZero() {
+			return Claim{}, errtrace.Errorf("claim %q has no dateFrom to determine the patient's age from and no reference date was given", c.ClaimID)
+		}
+		c.PatientDateOfBirth = deidentifyDateOfBirth(*c.PatientDateOfBirth, reference)
+	}
+	c.ClaimID = opts.Pseudonym(c.ClaimID)
+	c.AmbulancePickupZIP = deidentifyZIP(c.AmbulancePickupZIP)
+	c.DateFrom = shiftDate(c.DateFrom, shift)
+	c.DateThrough = shiftDate(c.DateThrough, shift)
+	c.Provider = c.Provider.deidentify()
+
+	services := make([]Service, len(c.Services))
+	for i, service := range c.Services {
+		service.Provider = service.Provider.deidentify()
+		service.AmbulancePickupZIP = deidentifyZIP(service.AmbulancePickupZIP)
+		service.DateFrom = shiftDate(service.DateFrom, shift)
+		service.DateThrough = shiftDate(service.DateThrough, shift)
+		services[i] = service
+	}
+	if c.Services != nil {
+		c.Services = services
+	}
+	return c, nil
+}
+
+// deidentify removes the provider's contact details.
+func (p Provider) deidentify() Provider {
+	p.ProviderPhones = nil
+	p.ProviderFaxes = nil
+	p.ProviderEmails = nil
+	return p
+}
+
+// deidentifyDateOfBirth reduces a date of birth to the year. Patients 90 or older at reference are aggregated.
+func deidentifyDateOfBirth(dob Date, reference Date) *Date {
+	if dob.Time.IsZero() {
+		return &dob
+	}
+	year := dob.Time.Year()
+	if dob.AgeOn(reference) >= maxAge {
+		year = reference.Time.Year() - maxAge
+	}
+	return NewDatePtr(year, 1, 1)
+}
+
+// deidentifyZIP reduces a ZIP code to its first 3 digits, or 000 if that area is sparsely populated.
+func deidentifyZIP(zip string) string {
+	if len(zip) < 3 {
+		return ""
+	}
+	zip3 := zip[:3]
+	if RestrictedZIP3[zip3] {
+		return "000"
+	}
+	return zip3
+}
+
+func shiftDate(d Date, days int) Date {
+	if d.Time.IsZero() || days == 0 {
+		return d
+	}
+	return d.AddDays(days)
+}
@@ -0,0 +1,121 @@
+package mph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeidentify(t *testing.T) {
+	t.Parallel()
+	opts := DeidentifyOptions{Key: []byte("secret"), MaxDateShiftDays: 30}
+	claim := Claim{
+		Provider:           Provider{NPI: "1962999664", ProviderZIP: "35960", ProviderPhones: []string{"555-1234"}, ProviderEmails: []string{"dr@example.com"}},
+		ClaimID:            "claim-1",
+		PatientDateOfBirth: NewDatePtr(1988, 6, 15),
+		AmbulancePickupZIP: "35960",
+		DRG:                "461",
+		BilledAmount:       47224,
+		DateFrom:           NewDate(2020, 2, 27),
+		DateThrough:        NewDate(2020, 3, 2),
+		Services: []Service{
+			{Provider: Provider{ProviderFaxes: []string{"555-9876"}}, LineNumber: "1", ProcedureCode: "76000", BilledAmount: 2126, DateFrom: NewDate(2020, 2, 28), AmbulancePickupZIP: "03601"},
+		},
+	}
+
+	deidentified, err := claim.Deidentify(opts)
+	require.NoError(t, err)
+	assert.Equal(t, opts.Pseudonym("claim-1"), deidentified.ClaimID)
+	assert.Len(t, deidentified.ClaimID, 32)
+	assert.NotEqual(t, "claim-1", deidentified.ClaimID)
+	assert.Equal(t, NewDatePtr(1988, 1, 1), deidentified.PatientDateOfBirth)
+	assert.Equal(t, "359", deidentified.AmbulancePickupZIP)
+	assert.Equal(t, "000", deidentified.Services[0].AmbulancePickupZIP)
+	assert.Nil(t, deidentified.ProviderPhones)
+	assert.Nil(t, deidentified.ProviderEmails)
+	assert.Nil(t, deidentified.Services[0].ProviderFaxes)
+
+	// pricing fields are preserved
+	assert.Equal(t, "1962999664", deidentified.NPI)
+	assert.Equal(t, "35960", deidentified.ProviderZIP)
+	assert.Equal(t, "461", deidentified.DRG)
+	assert.Equal(t, 47224.0, deidentified.BilledAmount)
+	assert.Equal(t, "76000", deidentified.Services[0].ProcedureCode)
+
+	// dates are shifted consistently
+	shift := opts.DateShift("claim-1")
+	assert.NotZero(t, shift)
+	assert.LessOrEqual(t, shift, 30)
+	assert.GreaterOrEqual(t, shift, -30)
+	assert.Equal(t, claim.DateFrom.Time.AddDate(0, 0, shift), deidentified.DateFrom.Time)
+	assert.Equal(t, 4*24.0, deidentified.DateThrough.Time.Sub(deidentified.DateFrom.Time).Hours())
+	assert.Equal(t, 24.0, deidentified.Services[0].DateFrom.Time.Sub(deidentified.DateFrom.Time).Hours())
+	assert.True(t, deidentified.Services[0].DateThrough.Time.IsZero())
+
+	// the original claim is not modified
+	assert.Equal(t, "claim-1", claim.ClaimID)
+	assert.Equal(t, NewDatePtr(1988, 6, 15), claim.PatientDateOfBirth)
+	assert.Equal(t, []string{"555-9876"}, claim.Services[0].ProviderFaxes)
+	assert.Equal(t, NewDate(2020, 2, 28), claim.Services[0].DateFrom)
+
+	// de-identification is repeatable
+	again, err := claim.Deidentify(opts)
+	require.NoError(t, err)
+	assert.Equal(t, deidentified, again)
+	other, err := claim.Deidentify(DeidentifyOptions{Key: []byte("other"), KeepDates: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, deidentified.ClaimID, other.ClaimID)
+	assert.Equal(t, claim.DateFrom, other.DateFrom)
+}
+
+func TestDeidentifyAge(t *testing.T) {
+	t.Parallel()
+	opts := DeidentifyOptions{Key: []byte("secret"), KeepDates: true}
+	claim, err := Claim{ClaimID: "1", PatientDateOfBirth: NewDatePtr(1920, 5, 1), DateFrom: NewDate(2024, 1, 1)}.Deidentify(opts)
+	require.NoError(t, err)
+	assert.Equal(t, NewDatePtr(1934, 1, 1), claim.PatientDateOfBirth)
+
+	claim, err = Claim{ClaimID: "1", PatientDateOfBirth: NewDatePtr(1940, 5, 1), DateFrom: NewDate(2024, 1, 1)}.Deidentify(opts)
+	require.NoError(t, err)
+	assert.Equal(t, NewDatePtr(1940, 1, 1), claim.PatientDateOfBirth)
+
+	// patients are aggregated from their 90th birthday
+	for _, dob := range []*Date{NewDatePtr(1934, 1, 2), NewDatePtr(1934, 1, 1), NewDatePtr(1933, 1, 2)} {
+		claim, err = Claim{ClaimID: "1", PatientDateOfBirth: dob, DateFrom: NewDate(2024, 1, 1)}.Deidentify(opts)
+		require.NoError(t, err)
+		assert.Equal(t, NewDatePtr(1934, 1, 1), claim.PatientDateOfBirth)
+	}
+
+	// the reference date is used when the claim has no dateFrom
+	_, err = Claim{ClaimID: "1", PatientDateOfBirth: NewDatePtr(1920, 5, 1)}.Deidentify(opts)
+	assert.ErrorContains(t, err, "no dateFrom")
+	opts.ReferenceDate = NewDate(2020, 6, 1)
+	claim, err = Claim{ClaimID: "1", PatientDateOfBirth: NewDatePtr(1920, 5, 1)}.Deidentify(opts)
+	require.NoError(t, err)
+	assert.Equal(t, NewDatePtr(1930, 1, 1), claim.PatientDateOfBirth)
+
+	claim, err = Claim{ClaimID: "1", AmbulancePickupZIP: "12"}.Deidentify(opts)
+	require.NoError(t, err)
+	assert.Empty(t, claim.AmbulancePickupZIP)
+	assert.Nil(t, claim.PatientDateOfBirth)
+	assert.Nil(t, claim.Services)
+}
+
+func TestDeidentifyOptions(t *testing.T) {
+	t.Parallel()
+	claim := Claim{ClaimID: "1", DateFrom: NewDate(2024, 1, 1)}
+
+	_, err := claim.Deidentify(DeidentifyOptions{})
+	assert.ErrorContains(t, err, "key is required")
+	_, err = claim.Deidentify(DeidentifyOptions{Key: []byte("secret")})
+	assert.ErrorContains(t, err, "MaxDateShiftDays must be positive")
+	_, err = claim.Deidentify(DeidentifyOptions{Key: []byte("secret"), MaxDateShiftDays: 30, KeepDates: true})
+	assert.ErrorContains(t, err, "cannot be set when KeepDates is set")
+	_, err = Claim{}.Deidentify(DeidentifyOptions{Key: []byte("secret"), KeepDates: true})
+	assert.ErrorContains(t, err, "claim ID is required")
+
+	kept, err := claim.Deidentify(DeidentifyOptions{Key: []byte("secret"), KeepDates: true})
+	require.NoError(t, err)
+	assert.Equal(t, claim.DateFrom, kept.DateFrom)
+}
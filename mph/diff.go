@@ -0,0 +1,215 @@
+package mph
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeKind describes how a service differs between two pricing results.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"    // only present in the new pricing
+	ChangeRemoved  ChangeKind = "removed"  // only present in the old pricing
+	ChangeModified ChangeKind = "modified" // present in both, but with different values
+)
+
+// FieldChange describes a single field which differs between two pricing results.
+type FieldChange struct {
+	Path    string   `json:"path"`             // JSON path of the field relative to the claim or service (e.g. inpatientPriceDetail.drgAmount)
+	Old     any      `json:"old"`              // Value of the field in the old pricing
+	New     any      `json:"new"`              // Value of the field in the new pricing
+	Delta   float64  `json:"delta,omitzero"`   // Absolute change from old to new (amounts only)
+	Percent *float64 `json:"percent,omitzero"` // Percent change from old to new (amounts only, nil when the old amount is zero)
+}
+
+// IsAmount returns true if the change is to a dollar amount or other floating point value.
+func (c FieldChange) IsAmount() bool {
+	_, ok := c.Old.(float64)
+	return ok
+}
+
+// ServiceDiff describes how a single service line differs between two pricing results.
+type ServiceDiff struct {
+	LineNumber string        `json:"lineNumber"`        // Line number used to align the services
+	Position   int           `json:"position,omitzero"` // 1-based position used to align the services when line numbers are missing or repeated
+	Kind       ChangeKind    `json:"kind"`              // Whether the service was added, removed or modified
+	Changes    []FieldChange `json:"changes,omitzero"`  // Field changes for modified services
+}
+
+// PricingDiff describes how two pricing results for the same claim differ.
+type PricingDiff struct {
+	ClaimID  string        `json:"claimID,omitzero"`  // The unique identifier for the claim (from the new pricing, or the old if not set)
+	Changes  []FieldChange `json:"changes,omitzero"`  // Claim-level changes including inpatient, outpatient, provider and edit detail
+	Services []ServiceDiff `json:"services,omitzero"` // Services which were added, removed or modified
+}
+
+func (d PricingDiff) IsEmpty() bool {
+	return len(d.Changes) == 0 && len(d.Services) == 0
+}
+
+// DiffPricing compares two pricing results field by field. Services are aligned by LineNumber and reported
+// in the order they appear in a followed by any services which only appear in b. If either result has a service
+// with an empty or repeated line number, services are aligned by their position instead.
+func DiffPricing(a, b Pricing) PricingDiff {
+	diff := PricingDiff{ClaimID: b.ClaimID}
+	if diff.ClaimID == "" {
+		diff.ClaimID = a.ClaimID
+	}
+	diff.Changes = diffStruct(nil, "", reflect.ValueOf(a), reflect.ValueOf(b), "claimID", "services")
+	if uniqueLineNumbers(a.Services) && uniqueLineNumbers(b.Services) {
+		diff.Services = diffServicesByLineNumber(a.Services, b.Services)
+	} else {
+		diff.Services = diffServicesByPosition(a.Services, b.Services)
+	}
+	return diff
+}
+
+func diffServicesByLineNumber(a, b PricedServices) []ServiceDiff {
+	var diffs []ServiceDiff
+	bServices := make(map[string]PricedService, len(b))
+	for _, s := range b {
+		bServices[s.LineNumber] = s
+	}
+	aServices := make(map[string]struct{}, len(a))
+	for _, aService := range a {
+		aServices[aService.LineNumber] = struct{}{}
+		bService, ok := bServices[aService.LineNumber]
+		if !ok {
+			diffs = append(diffs, ServiceDiff{LineNumber: aService.LineNumber, Kind: ChangeRemoved})
+			continue
+		}
+		if changes := diffService(aService, bService); len(changes) > 0 {
+			diffs = append(diffs, ServiceDiff{LineNumber: aService.LineNumber, Kind: ChangeModified, Changes: changes})
+		}
+	}
+	for _, bService := range b {
+		if _, ok := aServices[bService.LineNumber]; !ok {
+			diffs = append(diffs, ServiceDiff{LineNumber: bService.LineNumber, Kind: ChangeAdded})
+		}
+	}
+	return diffs
+}
+
+// diffServicesByPosition compares the services at the same position. Changes to the line number are reported
+// since the line number was not used to align the services.
+func diffServicesByPosition(a, b PricedServices) []ServiceDiff {
+	var diffs []ServiceDiff
+	for i := range max(len(a), len(b)) {
+		switch {
+		case i >= len(b):
+			diffs = append(diffs, ServiceDiff{LineNumber: a[i].LineNumber, Position: i + 1, Kind: ChangeRemoved})
+		case i >= len(a):
+			diffs = append(diffs, ServiceDiff{LineNumber: b[i].LineNumber, Position: i + 1, Kind: ChangeAdded})
+		default:
+			changes := diffStruct(nil, "", reflect.ValueOf(a[i]), reflect.ValueOf(b[i]))
+			if len(changes) > 0 {
+				diffs = append(diffs, ServiceDiff{LineNumber: a[i].LineNumber, Position: i + 1, Kind: ChangeModified, Changes: changes})
+			}
+		}
+	}
+	return diffs
+}
+
+func diffService(a, b PricedService) []FieldChange {
+	return diffStruct(nil, "", reflect.ValueOf(a), reflect.ValueOf(b), "lineNumber")
+}
+
+// uniqueLineNumbers returns true if every service has a line number which no other service has.
+func uniqueLineNumbers(services PricedServices) bool {
+	seen := make(map[string]struct{}, len(services))
+	for _, s := range services {
+		if _, ok := seen[s.LineNumber]; ok || s.LineNumber == "" {
+			return false
+		}
+		seen[s.LineNumber] = struct{}{}
+	}
+	return true
+}
+
+// PercentChange returns the percent change from oldValue to newValue or nil if oldValue is zero.
+func PercentChange(oldValue, newValue float64) *float64 {
+	if oldValue == 0 {
+		return nil
+	}
+	percent := (newValue - oldValue) / math.Abs(oldValue) * 100
+	return &percent
+}
+
+var responseErrorType = reflect.TypeFor[*ResponseError]()
+
+func diffStruct(changes []FieldChange, prefix string, a, b reflect.Value, skip ...string) []FieldChange {
+	t := a.Type()
+	for i := range t.NumField() {
+		name := jsonFieldName(t.Field(i))
+		if name == "" || slices.Contains(skip, name) {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		changes = diffValue(changes, name, a.Field(i), b.Field(i))
+	}
+	return changes
+}
+
+func diffValue(changes []FieldChange, path string, a, b reflect.Value) []FieldChange {
+	if a.Type() == responseErrorType {
+		aErr, bErr := a.Interface().(*ResponseError).Error(), b.Interface().(*ResponseError).Error()
+		if aErr != bErr {
+			changes = append(changes, FieldChange{Path: path, Old: aErr, New: bErr})
+		}
+		return changes
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() && b.IsNil() {
+			return changes
+		}
+		return diffValue(changes, path, derefOrZero(a), derefOrZero(b))
+	case reflect.Struct:
+		return diffStruct(changes, path, a, b)
+	case reflect.Float32, reflect.Float64:
+		aAmount, bAmount := a.Float(), b.Float()
+		if aAmount != bAmount {
+			changes = append(changes, FieldChange{Path: path, Old: aAmount, New: bAmount, Delta: bAmount - aAmount, Percent: PercentChange(aAmount, bAmount)})
+		}
+		return changes
+	case reflect.Slice:
+		if (a.Len() != 0 || b.Len() != 0) && !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			changes = append(changes, FieldChange{Path: path, Old: a.Interface(), New: b.Interface()})
+		}
+		return changes
+	}
+
+	if !a.Equal(b) {
+		changes = append(changes, FieldChange{Path: path, Old: a.Interface(), New: b.Interface()})
+	}
+	return changes
+}
+
+func derefOrZero(v reflect.Value) reflect.Value {
+	if v.IsNil() {
+		return reflect.Zero(v.Type().Elem())
+	}
+	return v.Elem()
+}
+
+// jsonFieldName returns the JSON name of a struct field or an empty string if the field is not serialized.
+func jsonFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
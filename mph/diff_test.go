@@ -0,0 +1,95 @@
+package mph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPricing(t *testing.T) {
+	t.Parallel()
+	a := Pricing{
+		ClaimID:              "123",
+		MedicareAmount:       100,
+		MedicareSource:       MedicareSourceInpatient,
+		InpatientPriceDetail: InpatientPriceDetail{DRG: "470", DRGAmount: 90},
+		ProviderDetail:       ProviderDetail{CCN: "010001", MAC: 10112},
+		EditError:            ErrorEditSeeDetail,
+		Services: []PricedService{
+			{LineNumber: "1", MedicareAmount: 60},
+			{LineNumber: "2", MedicareAmount: 40},
+		},
+	}
+	b := Pricing{
+		ClaimID:              "123",
+		MedicareAmount:       100,
+		MedicareSource:       MedicareSourceInpatient,
+		InpatientPriceDetail: InpatientPriceDetail{DRG: "471", DRGAmount: 90},
+		ProviderDetail:       ProviderDetail{CCN: "010001", MAC: 10112, RuralIndicator: RuralIndicatorRural},
+		EditDetail:           &ClaimEdits{ClaimDenialReasons: []string{"denied"}},
+		Services: []PricedService{
+			{LineNumber: "1", MedicareAmount: 0, ProviderDetail: &ProviderDetail{CCN: "010002"}},
+			{LineNumber: "2", MedicareAmount: 40},
+			{LineNumber: "3", MedicareAmount: 25},
+		},
+	}
+
+	diff := DiffPricing(a, b)
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, "123", diff.ClaimID)
+	assert.Equal(t, []FieldChange{
+		{Path: "inpatientPriceDetail.drg", Old: "470", New: "471"},
+		{Path: "providerDetail.ruralIndicator", Old: RuralIndicatorUrban, New: RuralIndicatorRural},
+		{Path: "editDetail.claimDenialReasons", Old: EditReasons(nil), New: EditReasons{"denied"}},
+		{Path: "editError", Old: "claim edits failed: see editDetail for more information", New: ""},
+	}, diff.Changes)
+
+	minus100 := -100.0
+	require.Len(t, diff.Services, 2)
+	assert.Equal(t, ServiceDiff{LineNumber: "1", Kind: ChangeModified, Changes: []FieldChange{
+		{Path: "providerDetail.ccn", Old: "", New: "010002"},
+		{Path: "medicareAmount", Old: 60.0, New: 0.0, Delta: -60, Percent: &minus100},
+	}}, diff.Services[0])
+	assert.Equal(t, ServiceDiff{LineNumber: "3", Kind: ChangeAdded}, diff.Services[1])
+
+	assert.True(t, DiffPricing(a, a).IsEmpty())
+}
+
+func TestDiffPricingByPosition(t *testing.T) {
+	t.Parallel()
+	a := Pricing{Services: PricedServices{
+		{LineNumber: "1", MedicareAmount: 60},
+		{LineNumber: "1", MedicareAmount: 40},
+		{MedicareAmount: 10},
+	}}
+	b := Pricing{Services: PricedServices{
+		{LineNumber: "1", MedicareAmount: 60},
+		{LineNumber: "1", MedicareAmount: 45},
+	}}
+
+	diff := DiffPricing(a, b)
+	percent := 12.5
+	assert.Equal(t, []ServiceDiff{
+		{LineNumber: "1", Position: 2, Kind: ChangeModified, Changes: []FieldChange{{Path: "medicareAmount", Old: 40.0, New: 45.0, Delta: 5, Percent: &percent}}},
+		{Position: 3, Kind: ChangeRemoved},
+	}, diff.Services)
+
+	// services without line numbers are also aligned by position
+	diff = DiffPricing(Pricing{Services: PricedServices{{}, {MedicareAmount: 1}}}, Pricing{Services: PricedServices{{}, {MedicareAmount: 1}, {}}})
+	assert.Equal(t, []ServiceDiff{{Position: 3, Kind: ChangeAdded}}, diff.Services)
+}
+
+func TestFieldChangeIsAmount(t *testing.T) {
+	t.Parallel()
+	assert.True(t, FieldChange{Old: 1.0, New: 2.0}.IsAmount())
+	assert.False(t, FieldChange{Old: "a", New: "b"}.IsAmount())
+}
+
+func TestPercentChange(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, PercentChange(0, 10))
+	require.NotNil(t, PercentChange(50, 75))
+	assert.InDelta(t, 50, *PercentChange(50, 75), 0.0001)
+	assert.InDelta(t, -50, *PercentChange(-50, -75), 0.0001)
+}
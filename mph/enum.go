@@ -0,0 +1,490 @@
+package mph
+
+import (
+	"encoding"
+	"strconv"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Category groups the values of a code type which are handled alike, such as the line repricing codes which explain
+// why a line was not priced.
+type Category string
+
+const (
+	CategoryPricingMethod Category = "pricing method"          // the amount was calculated using this method (repricing codes)
+	CategoryZeroDollar    Category = "zero-dollar explanation" // explains why no amount was calculated (repricing codes)
+	CategoryFeeSchedule   Category = "fee schedule"            // priced using a Medicare fee schedule (Medicare sources)
+	CategoryPricer        Category = "pricer"                  // priced using a Medicare prospective payment system pricer (Medicare sources)
+	CategoryEstimate      Category = "estimate"                // estimated because the claim could not be priced exactly (Medicare sources)
+	CategoryOther         Category = "other"                   // neither priced nor estimated by Medicare rules (Medicare sources)
+	CategoryAcuteCare     Category = "acute care"              // general acute care hospitals (hospital types)
+	CategorySpecialty     Category = "specialty"               // hospitals which are paid differently than acute care hospitals (hospital types)
+	CategoryProfessional  Category = "professional"            // professional claims (form types)
+	CategoryInstitutional Category = "institutional"           // institutional claims (form types)
+	CategoryOriginal      Category = "original"                // the first claim for the services (bill type sequences)
+	CategoryInterim       Category = "interim"                 // one of several claims for a continuing stay (bill type sequences)
+	CategoryAdjustment    Category = "adjustment"              // changes or cancels an earlier claim (bill type sequences)
+	CategoryKnown         Category = "known"                   // the value is known (sex types)
+	CategoryUnknown       Category = "unknown"                 // the value was not provided (sex types)
+)
+
+// enumValue describes a value of a code type.
+type enumValue[T comparable] struct {
+	value       T
+	description string
+	category    Category
+}
+
+// enum lists the values of a code type in the order they are documented.
+type enum[T comparable] []enumValue[T]
+
+// get returns the description of v, or the zero enumValue if v is not listed.
+func (e enum[T]) get(v T) (enumValue[T], bool) {
+	for _, ev := range e {
+		if ev.value == v {
+			return ev, true
+		}
+	}
+	return enumValue[T]{}, false
+}
+
+// description returns the description of v, or an empty string if v is not listed.
+func (e enum[T]) description(v T) string {
+	ev, _ := e.get(v)
+	return ev.description
+}
+
+// category returns the category of v, or an empty string if v is not listed.
+func (e enum[T]) category(v T) Category {
+	ev, _ := e.get(v)
+	return ev.category
+}
+
+// isValid returns true if v is listed.
+func (e enum[T]) isValid(v T) bool {
+	_, ok := e.get(v)
+	return ok
+}
+
+// set returns the values of e as a set.
+func (e enum[T]) set() map[T]struct{} {
+	set := make(map[T]struct{}, len(e))
+	for _, ev := range e {
+		set[ev.value] = struct{}{}
+	}
+	return set
+}
+
+// values returns the listed values in order.
+func (e enum[T]) values() []T {
+	values := make([]T, len(e))
+	for i, ev := range e {
+		values[i] = ev.value
+	}
+	return values
+}
+
+// decodeEnum sets *dst to v. When strict (see StrictDecoding), values which are not listed by e are rejected. The zero
+// value is always accepted since it means that the value was not supplied.
+func decodeEnum[T comparable](e enum[T], dst *T, v T, strict bool) error {
+	if strict {
+		if err := checkCode(v, e.isValid(v)); err != nil {
+			return errtrace.Wrap(err)
+		}
+	}
+	*dst = v
+	return nil
+}
+
+// checkCode returns an error unless v is valid or the zero value.
+func checkCode[T comparable](v T, valid bool) error {
+	var zero T
+	if !valid && v != zero {
+		return errtrace.Errorf("unknown %T value %v", v, v)
+	}
+	return nil
+}
+
+// ParseCode parses s as a code of type T in the format of its UnmarshalText method, such as
+// ParseCode[FormType]("UB-04", true). UnmarshalText accepts values which are not listed, like decoding JSON does by
+// default. When strict, ParseCode instead rejects them, except for the zero value since it means that the value was
+// not supplied.
+func ParseCode[T interface {
+	comparable
+	IsValid() bool
+}, P interface {
+	*T
+	encoding.TextUnmarshaler
+}](s string, strict bool) (T, error) {
+	var v T
+	if err := P(&v).UnmarshalText([]byte(s)); err != nil {
+		return v, errtrace.Wrap(err)
+	}
+	if strict {
+		if err := checkCode(v, v.IsValid()); err != nil {
+			var zero T
+			return zero, errtrace.Wrap(err)
+		}
+	}
+	return v, nil
+}
+
+// unmarshalStringEnum decodes a JSON string into a code type using decodeEnum, decoding strictly if the options of
+// dec do.
+func unmarshalStringEnum[T ~string](e enum[T], dst *T, dec *jsontext.Decoder) error {
+	var s string
+	if err := json.UnmarshalDecode(dec, &s); err != nil {
+		return errtrace.Wrap(err)
+	}
+	return errtrace.Wrap(decodeEnum(e, dst, T(s), isStrict(dec.Options())))
+}
+
+var claimRepricingCodes = enum[ClaimRepricingCode]{
+	{ClaimRepricingCodeMedicare, "Medicare", CategoryPricingMethod},
+	{ClaimRepricingCodeContractPricing, "Contract pricing", CategoryPricingMethod},
+	{ClaimRepricingCodeRBPPricing, "Reference-based pricing", CategoryPricingMethod},
+	{ClaimRepricingCodeCoralRBPPricing, "Coral reference-based pricing", CategoryPricingMethod},
+	{ClaimRepricingCodeSingleCaseAgreement, "Single case agreement", CategoryPricingMethod},
+	{ClaimRepricingCodeNeedsMoreInfo, "More information is needed to price the claim", CategoryZeroDollar},
+	{ClaimRepricingCodeOutOfNetwork, "Out of network", CategoryZeroDollar},
+}
+
+// Description returns a short explanation of the code, or an empty string if the code is not valid.
+func (c ClaimRepricingCode) Description() string {
+	return claimRepricingCodes.description(c)
+}
+
+// Category returns whether the code is a pricing method or explains why the claim was not priced.
+func (c ClaimRepricingCode) Category() Category {
+	return claimRepricingCodes.category(c)
+}
+
+// IsValid returns true if the code is one of the values returned by Values.
+func (c ClaimRepricingCode) IsValid() bool {
+	return claimRepricingCodes.isValid(c)
+}
+
+// Values returns every valid claim repricing code.
+func (*ClaimRepricingCode) Values() []ClaimRepricingCode {
+	return claimRepricingCodes.values()
+}
+
+// UnmarshalJSON decodes the ClaimRepricingCode with the default options, accepting values which are not listed.
+func (c *ClaimRepricingCode) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, c))
+}
+
+// UnmarshalJSONFrom decodes the ClaimRepricingCode, rejecting values which are not listed when dec decodes strictly.
+func (c *ClaimRepricingCode) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return errtrace.Wrap(unmarshalStringEnum(claimRepricingCodes, c, dec))
+}
+
+// UnmarshalText decodes the code, accepting values which are not listed. Use ParseCode to reject them.
+func (c *ClaimRepricingCode) UnmarshalText(data []byte) error {
+	return errtrace.Wrap(decodeEnum(claimRepricingCodes, c, ClaimRepricingCode(data), false))
+}
+
+var lineRepricingCodes = enum[LineRepricingCode]{
+	{LineRepricingCodeMedicare, "Medicare", CategoryPricingMethod},
+	{LineRepricingCodeMedicarePercent, "Percent of Medicare", CategoryPricingMethod},
+	{LineRepricingCodeMedicareNoOutlier, "Medicare without outlier payments", CategoryPricingMethod},
+	{LineRepricingCodeSyntheticMedicare, "Synthetic Medicare", CategoryPricingMethod},
+	{LineRepricingCodeBilledPercent, "Percent of billed charges", CategoryPricingMethod},
+	{LineRepricingCodeFeeSchedule, "Fee schedule", CategoryPricingMethod},
+	{LineRepricingCodePerDiem, "Per diem", CategoryPricingMethod},
+	{LineRepricingCodeFlatRate, "Flat rate", CategoryPricingMethod},
+	{LineRepricingCodeCostPercent, "Percent of cost", CategoryPricingMethod},
+	{LineRepricingCodeLimitedToBilled, "Limited to billed charges", CategoryPricingMethod},
+	{LineRepricingCodeNotRepricedPerRequest, "Not repriced per request", CategoryZeroDollar},
+	{LineRepricingCodeNotAllowedByMedicare, "Not allowed by Medicare", CategoryZeroDollar},
+	{LineRepricingCodePackaged, "Packaged into the payment for another service", CategoryZeroDollar},
+	{LineRepricingCodeNeedsMoreInfo, "More information is needed to price the service", CategoryZeroDollar},
+	{LineRepricingCodeProcedureCodeProblem, "Problem with the procedure code", CategoryZeroDollar},
+	{LineRepricingCodeOutOfNetwork, "Out of network", CategoryZeroDollar},
+}
+
+// Description returns a short explanation of the code, or an empty string if the code is not valid.
+func (c LineRepricingCode) Description() string {
+	return lineRepricingCodes.description(c)
+}
+
+// Category returns whether the code is a pricing method or explains why the line has a zero-dollar amount.
+func (c LineRepricingCode) Category() Category {
+	return lineRepricingCodes.category(c)
+}
+
+// IsValid returns true if the code is one of the values returned by Values.
+func (c LineRepricingCode) IsValid() bool {
+	return lineRepricingCodes.isValid(c)
+}
+
+// Values returns every valid line repricing code.
+func (*LineRepricingCode) Values() []LineRepricingCode {
+	return lineRepricingCodes.values()
+}
+
+// UnmarshalJSON decodes the LineRepricingCode with the default options, accepting values which are not listed.
+func (c *LineRepricingCode) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, c))
+}
+
+// UnmarshalJSONFrom decodes the LineRepricingCode, rejecting values which are not listed when dec decodes strictly.
+func (c *LineRepricingCode) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return errtrace.Wrap(unmarshalStringEnum(lineRepricingCodes, c, dec))
+}
+
+// UnmarshalText decodes the code, accepting values which are not listed. Use ParseCode to reject them.
+func (c *LineRepricingCode) UnmarshalText(data []byte) error {
+	return errtrace.Wrap(decodeEnum(lineRepricingCodes, c, LineRepricingCode(data), false))
+}
+
+var medicareSources = enum[MedicareSource]{
+	{MedicareSourceAmbulance, "Ambulance fee schedule", CategoryFeeSchedule},
+	{MedicareSourceAnesthesia, "Anesthesia fee schedule", CategoryFeeSchedule},
+	{MedicareSourceASC, "Ambulatory surgical center pricer", CategoryPricer},
+	{MedicareSourceCriticalAccessHospital, "Critical access hospital pricer", CategoryPricer},
+	{MedicareSourceDME, "Durable medical equipment fee schedule", CategoryFeeSchedule},
+	{MedicareSourceDrugs, "Drug fee schedule", CategoryFeeSchedule},
+	{MedicareSourceEditError, "Not priced because the claim failed edits", CategoryOther},
+	{MedicareSourceEstimateByCodeOnly, "Estimated from the procedure code", CategoryEstimate},
+	{MedicareSourceEstimateByLocalityCode, "Estimated from the procedure code in the provider's locality", CategoryEstimate},
+	{MedicareSourceEstimateByLocalityOnly, "Estimated from the provider's locality", CategoryEstimate},
+	{MedicareSourceEstimateByNational, "Estimated from national amounts", CategoryEstimate},
+	{MedicareSourceEstimateByStateCode, "Estimated from the procedure code in the provider's state", CategoryEstimate},
+	{MedicareSourceEstimateByStateOnly, "Estimated from the provider's state", CategoryEstimate},
+	{MedicareSourceEstimateByUnknown, "Estimated by an unknown method", CategoryEstimate},
+	{MedicareSourceInpatient, "Inpatient prospective payment system pricer", CategoryPricer},
+	{MedicareSourceLabs, "Clinical laboratory fee schedule", CategoryFeeSchedule},
+	{MedicareSourceMPFS, "Medicare physician fee schedule", CategoryFeeSchedule},
+	{MedicareSourceOutpatient, "Outpatient prospective payment system pricer", CategoryPricer},
+	{MedicareSourceManualPricing, "Priced manually", CategoryOther},
+	{MedicareSourceSNF, "Skilled nursing facility prospective payment system pricer", CategoryPricer},
+	{MedicareSourceSynthetic, "Synthetic Medicare based on the billed amount", CategoryOther},
+}
+
+// Description returns a short explanation of the source, or an empty string if the source is not valid.
+func (s MedicareSource) Description() string {
+	return medicareSources.description(s)
+}
+
+// Category returns whether the source is a fee schedule, a pricer, an estimate or other.
+func (s MedicareSource) Category() Category {
+	return medicareSources.category(s)
+}
+
+// IsValid returns true if the source is one of the values returned by Values.
+func (s MedicareSource) IsValid() bool {
+	return medicareSources.isValid(s)
+}
+
+// Values returns every valid Medicare source.
+func (*MedicareSource) Values() []MedicareSource {
+	return medicareSources.values()
+}
+
+// UnmarshalJSON decodes the MedicareSource with the default options, accepting values which are not listed.
+func (s *MedicareSource) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, s))
+}
+
+// UnmarshalJSONFrom decodes the MedicareSource, rejecting values which are not listed when dec decodes strictly.
+func (s *MedicareSource) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return errtrace.Wrap(unmarshalStringEnum(medicareSources, s, dec))
+}
+
+// UnmarshalText decodes the source, accepting values which are not listed. Use ParseCode to reject them.
+func (s *MedicareSource) UnmarshalText(data []byte) error {
+	return errtrace.Wrap(decodeEnum(medicareSources, s, MedicareSource(data), false))
+}
+
+var hospitalTypes = enum[HospitalType]{
+	{AcuteCareHospitalType, "Acute care hospital", CategoryAcuteCare},
+	{CriticalAccessHospitalType, "Critical access hospital", CategorySpecialty},
+	{ChildrensHospitalType, "Children's hospital", CategorySpecialty},
+	{PsychiatricHospitalType, "Psychiatric hospital", CategorySpecialty},
+	{AcuteCareDODHospitalType, "Department of Defense acute care hospital", CategoryAcuteCare},
+}
+
+// Description returns a short explanation of the hospital type, or an empty string if the type is not valid.
+func (h HospitalType) Description() string {
+	return hospitalTypes.description(h)
+}
+
+// Category returns whether the hospital is an acute care or specialty hospital.
+func (h HospitalType) Category() Category {
+	return hospitalTypes.category(h)
+}
+
+// IsValid returns true if the hospital type is one of the values returned by Values.
+func (h HospitalType) IsValid() bool {
+	return hospitalTypes.isValid(h)
+}
+
+// Values returns every valid hospital type.
+func (*HospitalType) Values() []HospitalType {
+	return hospitalTypes.values()
+}
+
+// UnmarshalJSON decodes the HospitalType with the default options, accepting values which are not listed.
+func (h *HospitalType) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, h))
+}
+
+// UnmarshalJSONFrom decodes the HospitalType, rejecting values which are not listed when dec decodes strictly.
+func (h *HospitalType) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return errtrace.Wrap(unmarshalStringEnum(hospitalTypes, h, dec))
+}
+
+// UnmarshalText decodes the hospital type, accepting values which are not listed. Use ParseCode to reject them.
+func (h *HospitalType) UnmarshalText(data []byte) error {
+	return errtrace.Wrap(decodeEnum(hospitalTypes, h, HospitalType(data), false))
+}
+
+var formTypes = enum[FormType]{
+	{UBFormType, "UB-04 institutional claim", CategoryInstitutional},
+	{HCFAFormType, "HCFA (CMS-1500) professional claim", CategoryProfessional},
+}
+
+// Description returns a short explanation of the form type, or an empty string if the type is not valid.
+func (f FormType) Description() string {
+	return formTypes.description(f)
+}
+
+// Category returns whether the form is used for institutional or professional claims.
+func (f FormType) Category() Category {
+	return formTypes.category(f)
+}
+
+// IsValid returns true if the form type is one of the values returned by Values.
+func (f FormType) IsValid() bool {
+	return formTypes.isValid(f)
+}
+
+// Values returns every valid form type.
+func (*FormType) Values() []FormType {
+	return formTypes.values()
+}
+
+// UnmarshalJSON decodes the FormType with the default options, accepting values which are not listed.
+func (f *FormType) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, f))
+}
+
+// UnmarshalJSONFrom decodes the FormType, rejecting values which are not listed when dec decodes strictly.
+func (f *FormType) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return errtrace.Wrap(unmarshalStringEnum(formTypes, f, dec))
+}
+
+// UnmarshalText decodes the form type, accepting values which are not listed. Use ParseCode to reject them.
+func (f *FormType) UnmarshalText(data []byte) error {
+	return errtrace.Wrap(decodeEnum(formTypes, f, FormType(data), false))
+}
+
+var billTypeSequences = enum[BillTypeSequence]{
+	{NonPayBillTypeSequence, "Non-payment or zero claim", CategoryOriginal},
+	{AdmitThroughDischargeBillTypeSequence, "Admit through discharge claim", CategoryOriginal},
+	{FirstInterimBillTypeSequence, "Interim, first claim", CategoryInterim},
+	{ContinuingInterimBillTypeSequence, "Interim, continuing claim", CategoryInterim},
+	{LastInterimBillTypeSequence, "Interim, last claim", CategoryInterim},
+	{LateChargeBillTypeSequence, "Late charges only", CategoryAdjustment},
+	{FirstInterimBillTypeSequenceDeprecated, "Interim, first claim (deprecated)", CategoryInterim},
+	{ReplacementBillTypeSequence, "Replacement of prior claim", CategoryAdjustment},
+	{VoidOrCancelBillTypeSequence, "Void or cancel of prior claim", CategoryAdjustment},
+	{FinalClaimBillTypeSequence, "Final claim for a home health episode", CategoryOriginal},
+	{CWFAdjustmentBillTypeSequence, "Common Working File initiated adjustment", CategoryAdjustment},
+	{CMSAdjustmentBillTypeSequence, "CMS initiated adjustment", CategoryAdjustment},
+	{IntermediaryAdjustmentBillTypeSequence, "Intermediary initiated adjustment", CategoryAdjustment},
+	{OtherAdjustmentBillTypeSequence, "Adjustment initiated for other reasons", CategoryAdjustment},
+	{OIGAdjustmentBillTypeSequence, "Office of Inspector General initiated adjustment", CategoryAdjustment},
+	{MSPAdjustmentBillTypeSequence, "Medicare Secondary Payer adjustment", CategoryAdjustment},
+	{QIOAdjustmentBillTypeSequence, "Quality Improvement Organization adjustment", CategoryAdjustment},
+	{ProviderAdjustmentBillTypeSequence, "Provider initiated adjustment", CategoryAdjustment},
+}
+
+// Description returns a short explanation of the sequence, or an empty string if the sequence is not valid.
+func (b BillTypeSequence) Description() string {
+	return billTypeSequences.description(b)
+}
+
+// Category returns whether the claim is an original, interim or adjustment claim.
+func (b BillTypeSequence) Category() Category {
+	return billTypeSequences.category(b)
+}
+
+// IsValid returns true if the sequence is one of the values returned by Values.
+func (b BillTypeSequence) IsValid() bool {
+	return billTypeSequences.isValid(b)
+}
+
+// Values returns every valid bill type sequence.
+func (*BillTypeSequence) Values() []BillTypeSequence {
+	return billTypeSequences.values()
+}
+
+// UnmarshalJSON decodes the BillTypeSequence with the default options, accepting values which are not listed.
+func (b *BillTypeSequence) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, b))
+}
+
+// UnmarshalJSONFrom decodes the BillTypeSequence, rejecting values which are not listed when dec decodes strictly.
+func (b *BillTypeSequence) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return errtrace.Wrap(unmarshalStringEnum(billTypeSequences, b, dec))
+}
+
+// UnmarshalText decodes the sequence, accepting values which are not listed. Use ParseCode to reject them.
+func (b *BillTypeSequence) UnmarshalText(data []byte) error {
+	return errtrace.Wrap(decodeEnum(billTypeSequences, b, BillTypeSequence(data), false))
+}
+
+var sexTypes = enum[SexType]{
+	{SexTypeUnknown, "Unknown", CategoryUnknown},
+	{SexTypeMale, "Male", CategoryKnown},
+	{SexTypeFemale, "Female", CategoryKnown},
+}
+
+// Description returns the name of the sex type, or an empty string if the type is not valid.
+func (s SexType) Description() string {
+	return sexTypes.description(s)
+}
+
+// Category returns whether the sex of the patient is known.
+func (s SexType) Category() Category {
+	return sexTypes.category(s)
+}
+
+// IsValid returns true if the sex type is one of the values returned by Values.
+func (s SexType) IsValid() bool {
+	return sexTypes.isValid(s)
+}
+
+// Values returns every valid sex type.
+func (*SexType) Values() []SexType {
+	return sexTypes.values()
+}
+
+// UnmarshalJSON decodes the SexType with the default options, accepting values which are not listed.
+func (s *SexType) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, s))
+}
+
+// UnmarshalJSONFrom decodes the SexType, rejecting values which are not listed when dec decodes strictly.
+func (s *SexType) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var n uint8
+	if err := json.UnmarshalDecode(dec, &n); err != nil {
+		return errtrace.Wrap(err)
+	}
+	return errtrace.Wrap(decodeEnum(sexTypes, s, SexType(n), isStrict(dec.Options())))
+}
+
+// UnmarshalText decodes the number of the sex type, accepting values which are not listed. Use ParseCode to reject
+// them.
+func (s *SexType) UnmarshalText(data []byte) error {
+	n, err := strconv.ParseUint(string(data), 10, 8)
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	return errtrace.Wrap(decodeEnum(sexTypes, s, SexType(n), false))
+}
@@ -0,0 +1,130 @@
+package mph
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// codeType is implemented by each of the code types.
+type codeType interface {
+	comparable
+	Description() string
+	Category() Category
+	IsValid() bool
+}
+
+func checkCodeType[T codeType, P interface {
+	*T
+	Values() []T
+}](t *testing.T, invalid T) {
+	t.Helper()
+	var zero T
+	values := P(nil).Values()
+	require.NotEmpty(t, values)
+	assert.Len(t, slices.Compact(slices.Clone(values)), len(values), "%T values are unique", zero)
+	for _, v := range values {
+		assert.True(t, v.IsValid(), "%v", v)
+		assert.NotEmpty(t, v.Description(), "%v", v)
+		assert.NotEmpty(t, v.Category(), "%v", v)
+	}
+	assert.False(t, invalid.IsValid())
+	assert.Empty(t, invalid.Description())
+	assert.Empty(t, invalid.Category())
+}
+
+func TestCodeTypes(t *testing.T) {
+	t.Parallel()
+	checkCodeType(t, ClaimRepricingCode("XYZ"))
+	checkCodeType(t, LineRepricingCode("XYZ"))
+	checkCodeType(t, MedicareSource("Guess"))
+	checkCodeType(t, HospitalType("Veterinary"))
+	checkCodeType(t, FormType("UB-92"))
+	checkCodeType(t, BillTypeSequence("Z"))
+	checkCodeType(t, SexType(9))
+
+	// the lists of codes agree with the exported sets
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(ClaimRepricingCodes)), (*ClaimRepricingCode).Values(nil))
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(LineRepricingCodes)), (*LineRepricingCode).Values(nil))
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(MedicareSources)), (*MedicareSource).Values(nil))
+}
+
+func TestCodeCategories(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, CategoryPricingMethod, LineRepricingCodeFeeSchedule.Category())
+	assert.Equal(t, CategoryZeroDollar, LineRepricingCodeNotAllowedByMedicare.Category())
+	assert.Equal(t, CategoryZeroDollar, ClaimRepricingCodeNeedsMoreInfo.Category())
+	assert.Equal(t, CategoryEstimate, MedicareSourceEstimateByStateCode.Category())
+	assert.Equal(t, CategoryPricer, MedicareSourceInpatient.Category())
+	assert.Equal(t, CategorySpecialty, PsychiatricHospitalType.Category())
+	assert.Equal(t, CategoryInstitutional, UBFormType.Category())
+	assert.Equal(t, CategoryAdjustment, VoidOrCancelBillTypeSequence.Category())
+	assert.Equal(t, CategoryUnknown, SexTypeUnknown.Category())
+	assert.Equal(t, "Not allowed by Medicare", LineRepricingCodeNotAllowedByMedicare.Description())
+	assert.Equal(t, "Female", SexTypeFemale.Description())
+}
+
+func TestLenientCodeDecoding(t *testing.T) {
+	t.Parallel()
+	var claim Claim
+	require.NoError(t, Unmarshal([]byte(`{"formType":"UB-92","billTypeSequence":"Z","patientSex":9}`), &claim))
+	assert.Equal(t, Claim{FormType: "UB-92", BillTypeSequence: "Z", PatientSex: 9}, claim)
+
+	var code LineRepricingCode
+	require.NoError(t, code.UnmarshalText([]byte("XYZ")))
+	assert.Equal(t, LineRepricingCode("XYZ"), code)
+}
+
+func TestStrictCodeDecoding(t *testing.T) {
+	t.Parallel()
+	strict := StrictDecoding()
+
+	var claim Claim
+	require.NoError(t, Unmarshal([]byte(`{"formType":"UB-04","billTypeSequence":"7","patientSex":2}`), &claim, strict))
+	assert.Equal(t, Claim{FormType: UBFormType, BillTypeSequence: ReplacementBillTypeSequence, PatientSex: SexTypeFemale}, claim)
+	assert.ErrorContains(t, Unmarshal([]byte(`{"formType":"UB-92"}`), &claim, strict), "unknown mph.FormType value UB-92")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"billTypeSequence":"Z"}`), &claim, strict), "unknown mph.BillTypeSequence value Z")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"patientSex":9}`), &claim, strict), "unknown mph.SexType value 9")
+
+	var pricing Pricing
+	require.NoError(t, Unmarshal([]byte(`{"medicareRepricingCode":"MED","medicareSource":"IPPS","services":[{"medicareRepricingCode":"NAM"}]}`), &pricing, strict))
+	assert.ErrorContains(t, Unmarshal([]byte(`{"medicareRepricingCode":"XYZ"}`), &pricing, strict), "unknown mph.ClaimRepricingCode value XYZ")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"services":[{"allowedRepricingCode":"XYZ"}]}`), &pricing, strict), "unknown mph.LineRepricingCode value XYZ")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"medicareSource":"Guess"}`), &pricing, strict), "unknown mph.MedicareSource value Guess")
+	assert.ErrorContains(t, Unmarshal([]byte(`{"providerDetail":{"hospitalType":"Veterinary"}}`), &pricing, strict), "unknown mph.HospitalType value Veterinary")
+
+}
+
+func TestParseCode(t *testing.T) {
+	t.Parallel()
+	// UnmarshalText and lenient parsing accept values which are not listed
+	var sex SexType
+	require.NoError(t, sex.UnmarshalText([]byte("9")))
+	assert.Equal(t, SexType(9), sex)
+	sex, err := ParseCode[SexType]("9", false)
+	require.NoError(t, err)
+	assert.Equal(t, SexType(9), sex)
+	source, err := ParseCode[MedicareSource]("Guess", false)
+	require.NoError(t, err)
+	assert.Equal(t, MedicareSource("Guess"), source)
+
+	// strict parsing rejects them, except for the zero value
+	sex, err = ParseCode[SexType]("2", true)
+	require.NoError(t, err)
+	assert.Equal(t, SexTypeFemale, sex)
+	_, err = ParseCode[SexType]("9", true)
+	assert.ErrorContains(t, err, "unknown mph.SexType value 9")
+	_, err = ParseCode[SexType]("male", true)
+	assert.Error(t, err)
+	source, err = ParseCode[MedicareSource]("IPPS", true)
+	require.NoError(t, err)
+	assert.Equal(t, MedicareSourceInpatient, source)
+	_, err = ParseCode[MedicareSource]("Guess", true)
+	assert.ErrorContains(t, err, "unknown mph.MedicareSource value Guess")
+	form, err := ParseCode[FormType]("", true)
+	require.NoError(t, err)
+	assert.Empty(t, form)
+}
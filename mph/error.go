@@ -3,13 +3,26 @@ package mph
 import (
 	"database/sql"
 	"database/sql/driver"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// Sentinel errors describing why pricing failed. Errors returned by Response.GetError, ErrorAndResultResponses.GetError,
+// ErrorAndResult.Unwrap and Pricing.EditError match these using errors.Is.
+var (
+	ErrEditFailure        = errors.New("claim edits failed")    // the claim failed edits (also matched by fatal edit errors)
+	ErrFatalEdit          = errors.New("fatal edit error")      // the claim must be returned to the provider
+	ErrProviderNotMatched = errors.New("provider not matched")  // the provider could not be matched
+	ErrPricingUnavailable = errors.New("pricing not available") // the claim could not be priced
+	ErrAuthentication     = errors.New("authentication failed") // the API key is missing, invalid or not allowed to make the request
+	ErrRateLimited        = errors.New("rate limited")          // too many requests were made
+	ErrServerError        = errors.New("server error")          // the server failed to process the request
 )
 
 type Error struct {
@@ -23,8 +36,8 @@ func (e *Error) IsFatal() bool {
 }
 
 type errorJSON struct {
-	Title     string `json:"title,omitempty"`
-	ErrorCode int    `json:"errorCode,omitempty"`
+	Title     string `json:"title,omitzero"`
+	ErrorCode int    `json:"errorCode,omitzero"`
 }
 
 func (e *Error) Unwrap() error {
@@ -35,6 +48,25 @@ func (e *Error) Unwrap() error {
 	return e.Detail
 }
 
+// Is reports whether the status code of the error matches target. The title is matched through the
+// *ResponseError returned by Unwrap when the error came from an API response.
+func (e *Error) Is(target error) bool {
+	return e != nil && statusIs(e.ErrorCode, target)
+}
+
+// statusIs reports whether an HTTP status code matches target.
+func statusIs(statusCode int, target error) bool {
+	switch target {
+	case ErrAuthentication:
+		return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return statusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return statusCode >= 500 && statusCode <= 599
+	}
+	return false
+}
+
 func (e *Error) Error() string {
 	if e == nil {
 		return ""
@@ -42,24 +74,55 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("%s: %s", e.Title, e.Detail)
 }
 
-func (e *Error) MarshalJSON() ([]byte, error) {
-	if e == nil || e.Detail == nil {
-		return errtrace.Wrap2(json.Marshal(nil))
+// Problem returns a representation of the error which is safe to send to callers. The detail of server errors
+// (5xx or no status code) is replaced with the status text so that internal details are not exposed.
+func (e *Error) Problem() *ResponseError {
+	if e == nil {
+		return nil
+	}
+	status := e.ErrorCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	problem := &ResponseError{Title: e.Title, Status: status}
+	if status >= 500 || e.Detail == nil {
+		problem.Detail = http.StatusText(status)
+	} else {
+		problem.Detail = e.Detail.Error()
 	}
+	return problem
+}
 
-	if !e.IsFatal() {
+// MarshalJSON encodes only the title and error code so that internal details are never exposed. It panics for
+// non-fatal errors, which should be sent as a ResponseError, to catch misrouted errors. It is only used by
+// encoding/json: this package encodes errors with MarshalJSONTo, which does not panic.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	if e != nil && e.Detail != nil && !e.IsFatal() {
 		panic(errtrace.Errorf("cannot marshal non-fatal errors to JSON, use ResponseError instead"))
 	}
+	return errtrace.Wrap2(json.Marshal(e))
+}
 
-	return errtrace.Wrap2(json.Marshal(errorJSON{
+// MarshalJSONTo encodes only the title and error code so that internal details are never exposed.
+func (e *Error) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if e == nil || e.Detail == nil {
+		return errtrace.Wrap(enc.WriteToken(jsontext.Null))
+	}
+	return errtrace.Wrap(json.MarshalEncode(enc, errorJSON{
 		Title:     e.Title,
 		ErrorCode: e.ErrorCode,
 	}))
 }
 
+// UnmarshalJSON decodes the title and error code with the default options.
 func (e *Error) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, e))
+}
+
+// UnmarshalJSONFrom decodes the title and error code using the options of dec.
+func (e *Error) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	var ej errorJSON
-	if err := json.Unmarshal(data, &ej); err != nil {
+	if err := json.UnmarshalDecode(dec, &ej); err != nil {
 		return errtrace.Wrap(err)
 	}
 	e.Title = ej.Title
@@ -68,14 +131,18 @@ func (e *Error) UnmarshalJSON(data []byte) error {
 }
 
 var _ json.Marshaler = &Error{}
+var _ json.MarshalerTo = &Error{}
 var _ json.Unmarshaler = &Error{}
+var _ json.UnmarshalerFrom = &Error{}
 
+// ToResponseError converts a non-fatal error into a ResponseError. Fatal errors are converted using Problem so
+// that internal details are not exposed.
 func (e *Error) ToResponseError() *ResponseError {
 	if e == nil || e.Detail == nil || (e.Title == "" && e.Detail.Error() == "") {
 		return nil
 	}
 	if e.IsFatal() {
-		panic(errtrace.Errorf("fatal web.Errors cannot be converted to ResponseError"))
+		return e.Problem()
 	}
 	return &ResponseError{
 		Title:  e.Title,
@@ -83,9 +150,31 @@ func (e *Error) ToResponseError() *ResponseError {
 	}
 }
 
+// MustResponseError is like ToResponseError but panics for fatal errors, which is useful in tests to catch
+// misrouted errors.
+func (e *Error) MustResponseError() *ResponseError {
+	if e.IsFatal() {
+		panic(errtrace.Errorf("fatal web.Errors cannot be converted to ResponseError"))
+	}
+	return e.ToResponseError()
+}
+
+// ResponseError is an RFC 7807 problem document (https://tools.ietf.org/html/rfc7807). Members which are not
+// defined below are preserved in Extensions.
 type ResponseError struct {
-	Title  string `json:"title,omitempty"  db:"-"`
-	Detail string `json:"detail,omitempty" db:"-"`
+	Type       string                    `json:"type,omitzero"     db:"-"` // URI reference identifying the problem type
+	Title      string                    `json:"title,omitzero"    db:"-"` // Short summary of the problem type
+	Status     int                       `json:"status,omitzero"   db:"-"` // HTTP status code of the response
+	Detail     string                    `json:"detail,omitzero"   db:"-"` // Explanation specific to this occurrence of the problem
+	Instance   string                    `json:"instance,omitzero" db:"-"` // URI reference identifying this occurrence of the problem
+	Errors     []ValidationError         `json:"errors,omitzero"   db:"-"` // Validation errors in the request
+	Extensions map[string]jsontext.Value `json:",unknown"          db:"-"` // Extension members not defined above
+}
+
+// ValidationError describes a single problem with the request.
+type ValidationError struct {
+	Pointer string `json:"pointer,omitzero"` // JSON pointer (RFC 6901) to the invalid member of the request (e.g. /services/0/procedureCode)
+	Detail  string `json:"detail"`           // Explanation of the problem
 }
 
 var _ error = &ResponseError{}
@@ -122,6 +211,53 @@ func (r *ResponseError) Error() string {
 	return fmt.Sprintf("%s: %s", r.Title, r.Detail)
 }
 
+// Is reports whether the title or status of the error matches target (e.g. ErrEditFailure or ErrPricingUnavailable).
+func (r *ResponseError) Is(target error) bool {
+	if r == nil {
+		return false
+	}
+	if statusIs(r.Status, target) {
+		return true
+	}
+	switch target {
+	case ErrEditFailure:
+		return r.Title == editErrorTitle || r.Title == fatalEditErrorTitle
+	case ErrFatalEdit:
+		return r.Title == fatalEditErrorTitle
+	case ErrPricingUnavailable:
+		return r.Title == PriceErrorTitle
+	case ErrProviderNotMatched:
+		return r.Title == ProviderNotMatchedTitle
+	}
+	return false
+}
+
+// toError converts the response error into an *Error with the given status code, falling back to the status of
+// the problem document. The *ResponseError can be
+// retrieved from the result using errors.As.
+func (r *ResponseError) toError(statusCode int) *Error {
+	if r == nil {
+		return nil
+	}
+	if statusCode == 0 {
+		statusCode = r.Status
+	}
+	return NewError(r.Title, responseErrorDetail{r}, statusCode)
+}
+
+// responseErrorDetail is the detail of an *Error created from a *ResponseError.
+type responseErrorDetail struct {
+	err *ResponseError
+}
+
+func (d responseErrorDetail) Error() string {
+	return d.err.Detail
+}
+
+func (d responseErrorDetail) Unwrap() error {
+	return d.err
+}
+
 func (r ResponseError) String() string {
 	return r.Error()
 }
@@ -171,12 +307,21 @@ func ForbiddenError(detail error) *Error {
 	return NewError("Forbidden", detail, http.StatusForbidden)
 }
 
+// ClientError creates a 400 error. If detail wraps a fatal error, its status code is kept.
 func ClientError(title string, detail error) *Error {
 	var wErr *Error
-	if errors.As(detail, &wErr) {
-		if wErr.IsFatal() {
-			panic(errtrace.Errorf("cannot create client error from fatal error"))
-		}
+	if errors.As(detail, &wErr) && wErr.IsFatal() {
+		return NewError(title, detail, wErr.ErrorCode)
+	}
+	return NewError(title, detail, http.StatusBadRequest)
+}
+
+// MustClientError is like ClientError but panics if detail wraps a fatal error, which is useful in tests to catch
+// misrouted errors.
+func MustClientError(title string, detail error) *Error {
+	var wErr *Error
+	if errors.As(detail, &wErr) && wErr.IsFatal() {
+		panic(errtrace.Errorf("cannot create client error from fatal error"))
 	}
 	return NewError(title, detail, http.StatusBadRequest)
 }
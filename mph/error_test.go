@@ -1,9 +1,12 @@
 package mph
 
 import (
+	stdjson "encoding/json"
+	"net/http"
 	"testing"
 
 	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -37,6 +40,42 @@ func TestErrorMarshalJSON(t *testing.T) {
 	})
 }
 
+func TestErrorStrict(t *testing.T) {
+	t.Parallel()
+
+	fatal := InternalError("database unavailable", errtrace.Errorf("connection refused"))
+	assert.Panics(t, func() { fatal.MustResponseError() })
+	assert.Panics(t, func() { MustClientError("bad claim", fatal) })
+	assert.Equal(t, &ResponseError{Title: "bad claim", Detail: "missing NPI"}, MustClientError("bad claim", errtrace.Errorf("missing NPI")).MustResponseError())
+}
+
+func TestErrorNotStrict(t *testing.T) {
+	t.Parallel()
+
+	e := &Error{Title: "title", Detail: errtrace.Errorf("detail"), ErrorCode: 400}
+	got, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Equal(t, `{"title":"title","errorCode":400}`, string(got))
+	got, err = json.Marshal((*Error)(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(got))
+
+	fatal := InternalError("database unavailable", errtrace.Errorf("connection refused"))
+	assert.Equal(t, &ResponseError{Title: "database unavailable", Detail: "Internal Server Error", Status: 500}, fatal.ToResponseError())
+	clientErr := ClientError("bad claim", fatal)
+	assert.True(t, clientErr.IsFatal(), "fatal errors should not become client errors")
+}
+
+func TestErrorProblem(t *testing.T) {
+	t.Parallel()
+
+	var e *Error
+	assert.Nil(t, e.Problem())
+	assert.Equal(t, &ResponseError{Title: "bad claim", Detail: "missing NPI", Status: 400}, ClientError("bad claim", errtrace.Errorf("missing NPI")).Problem())
+	assert.Equal(t, &ResponseError{Title: "timeout", Detail: "Bad Gateway", Status: 502}, NewError("timeout", errtrace.Errorf("upstream 10.0.0.1 timed out"), 502).Problem())
+	assert.Equal(t, &ResponseError{Title: "unknown", Detail: "Internal Server Error", Status: 500}, (&Error{Title: "unknown", Detail: errtrace.Errorf("secret")}).Problem())
+}
+
 func TestErrorUnmarshalJSON(t *testing.T) {
 	t.Parallel()
 
@@ -62,3 +101,62 @@ func TestParseResponseError(t *testing.T) {
 	msg = ""
 	assert.Nil(t, ParseResponseError(msg))
 }
+
+func TestErrorIs(t *testing.T) {
+	t.Parallel()
+
+	response := Response[Pricing]{Error: &ResponseError{Title: PriceErrorTitle, Detail: "no rates"}, StatusCode: http.StatusBadRequest}
+	err := error(response.GetError())
+	assert.ErrorIs(t, err, ErrPricingUnavailable)
+	assert.NotErrorIs(t, err, ErrServerError)
+	assert.Equal(t, "pricing not available: no rates", err.Error())
+	var responseErr *ResponseError
+	require.ErrorAs(t, err, &responseErr)
+	assert.Equal(t, "no rates", responseErr.Detail)
+
+	responses := ErrorAndResultResponses[Pricing]{Error: &ResponseError{Title: "unauthorized", Detail: "invalid key"}, StatusCode: http.StatusUnauthorized}
+	assert.ErrorIs(t, responses.GetError(), ErrAuthentication)
+	responses.StatusCode = http.StatusTooManyRequests
+	assert.ErrorIs(t, responses.GetError(), ErrRateLimited)
+	responses.StatusCode = http.StatusBadGateway
+	assert.ErrorIs(t, responses.GetError(), ErrServerError)
+
+	_, resultErr := ErrorAndResult[Pricing]{Error: &ResponseError{Title: ProviderNotMatchedTitle, Detail: "NPI 123"}}.Unwrap()
+	assert.ErrorIs(t, resultErr, ErrProviderNotMatched)
+	assert.NotErrorIs(t, &ResponseError{Title: "provider has no matching rates"}, ErrProviderNotMatched)
+	assert.NoError(t, ErrorAndResult[Pricing]{}.Err())
+	assert.ErrorIs(t, ErrorAndResult[Pricing]{Error: ErrorEditSeeDetail}.Err(), ErrEditFailure)
+
+	p := Pricing{EditError: ErrorEditFatal}
+	assert.ErrorIs(t, p.EditError, ErrFatalEdit)
+	assert.ErrorIs(t, p.EditError, ErrEditFailure, "fatal edits are also edit failures")
+	assert.True(t, p.HasFatalError())
+	p.EditError = ErrorEditSeeDetail
+	assert.NotErrorIs(t, p.EditError, ErrFatalEdit)
+	assert.False(t, p.HasFatalError())
+	p.EditError = nil
+	assert.False(t, p.HasFatalError())
+}
+
+func FuzzErrorJSON(f *testing.F) {
+	for _, seed := range []string{`{"title": "title", "errorCode": 500}`, `{}`, `null`, `{"title": 1}`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var std, v2 Error
+		stdErr := stdjson.Unmarshal(data, &std)
+		v2Err := Unmarshal(data, &v2)
+		if !assert.Equal(t, stdErr == nil, v2Err == nil, "encoding/json error: %v, json v2 error: %v", stdErr, v2Err) || stdErr != nil {
+			return
+		}
+		assert.Equal(t, std, v2)
+
+		// only the title and error code are encoded, and only when there is a detail
+		e := Error{Title: v2.Title, ErrorCode: v2.ErrorCode, Detail: errtrace.Errorf("detail")}
+		encoded, err := json.Marshal(&e)
+		require.NoError(t, err)
+		var decoded Error
+		require.NoError(t, decoded.UnmarshalJSON(encoded))
+		assert.Equal(t, v2, decoded)
+	})
+}
@@ -0,0 +1,216 @@
+package mph
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+)
+
+// maxRequestBytes limits the size of request bodies accepted by the handler.
+const maxRequestBytes = 64 << 20
+
+const invalidRequestTitle = "invalid request"
+
+// handler serves the My Price Health API routes using a Pricer.
+type handler struct {
+	pricer Pricer
+	opts   json.Options // options used to decode requests
+}
+
+// NewHandler returns an http.Handler which serves the My Price Health API routes using p. It decodes claims and
+// rate sheets, parses the PriceConfig headers and writes the standard response envelopes. This allows any Pricer
+// (e.g. a local implementation or a caching proxy around Client) to be served with the same API as My Price Health.
+// Requests are decoded with the default options overridden by opts (e.g. StrictDecoding()).
+func NewHandler(p Pricer, opts ...json.Options) http.Handler {
+	h := handler{pricer: p, opts: decodeOptions(opts...)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/medicare/price/claim", h.price)
+	mux.HandleFunc("POST /v1/medicare/price/claims", h.priceBatch)
+	mux.HandleFunc("POST /v1/medicare/estimate/claims", h.estimateClaims)
+	mux.HandleFunc("POST /v1/medicare/estimate/rate-sheet", h.estimateRateSheet)
+	return Recover(mux, nil)
+}
+
+// Recover returns a handler which recovers from panics in next. The panic is logged and a 500 problem document is
+// written instead of crashing the server. Nothing is written if next already started the response. If logger is
+// nil, slog.Default is used.
+func Recover(next http.Handler, logger *slog.Logger) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &headerTracker{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			logger.ErrorContext(r.Context(), "recovered from panic", "method", r.Method, "path", r.URL.Path, "panic", v, "stack", string(debug.Stack()))
+			if !rw.wroteHeader {
+				_ = WriteProblem(w, InternalError("internal server error", errtrace.Errorf("panic: %v", v)).Problem())
+			}
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// headerTracker records whether the header of a response has been written.
+type headerTracker struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (t *headerTracker) WriteHeader(statusCode int) {
+	t.wroteHeader = true
+	t.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (t *headerTracker) Write(b []byte) (int, error) {
+	t.wroteHeader = true
+	return errtrace.Wrap2(t.ResponseWriter.Write(b))
+}
+
+// Unwrap allows http.ResponseController to reach the underlying ResponseWriter.
+func (t *headerTracker) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
+
+func (h handler) price(w http.ResponseWriter, r *http.Request) {
+	config, err := ParseHeaders(r)
+	if err != nil {
+		writeResponse(w, Response[Pricing]{Error: toResponseError(err, http.StatusBadRequest)})
+		return
+	}
+	var claim Claim
+	if err := h.decodeRequest(w, r, &claim); err != nil {
+		writeResponse(w, Response[Pricing]{Error: toResponseError(err, http.StatusBadRequest)})
+		return
+	}
+	writeResponse(w, h.pricer.Price(r.Context(), config, claim))
+}
+
+func (h handler) priceBatch(w http.ResponseWriter, r *http.Request) {
+	config, err := ParseHeaders(r)
+	if err != nil {
+		writeResponses(w, ErrorAndResultResponses[Pricing]{Error: toResponseError(err, http.StatusBadRequest)})
+		return
+	}
+	var claims []Claim
+	if err := h.decodeRequest(w, r, &claims); err != nil {
+		writeResponses(w, ErrorAndResultResponses[Pricing]{Error: toResponseError(err, http.StatusBadRequest)})
+		return
+	}
+	writeResponses(w, h.pricer.PriceBatch(r.Context(), config, claims...))
+}
+
+func (h handler) estimateClaims(w http.ResponseWriter, r *http.Request) {
+	var claims []Claim
+	if err := h.decodeRequest(w, r, &claims); err != nil {
+		writeResponses(w, ErrorAndResultResponses[Pricing]{Error: toResponseError(err, http.StatusBadRequest)})
+		return
+	}
+	writeResponses(w, h.pricer.EstimateClaims(r.Context(), claims...))
+}
+
+func (h handler) estimateRateSheet(w http.ResponseWriter, r *http.Request) {
+	var rateSheets []RateSheet
+	if err := h.decodeRequest(w, r, &rateSheets); err != nil {
+		writeResponses(w, ErrorAndResultResponses[Pricing]{Error: toResponseError(err, http.StatusBadRequest)})
+		return
+	}
+	writeResponses(w, h.pricer.EstimateRateSheet(r.Context(), rateSheets...))
+}
+
+func (h handler) decodeRequest(w http.ResponseWriter, r *http.Request, v any) error {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return errtrace.Wrap(NewError(invalidRequestTitle, err, http.StatusRequestEntityTooLarge))
+		}
+		return errtrace.Wrap(ClientError(invalidRequestTitle, err))
+	}
+	if err := json.Unmarshal(data, v, h.opts); err != nil {
+		return errtrace.Wrap(ClientError(invalidRequestTitle, err))
+	}
+	return nil
+}
+
+// toResponseError converts an error into a ResponseError using Error.Problem. Errors without a status code use statusCode.
+func toResponseError(err error, statusCode int) *ResponseError {
+	var e *Error
+	if !errors.As(err, &e) {
+		return &ResponseError{Title: invalidRequestTitle, Detail: err.Error(), Status: statusCode}
+	}
+	if e.ErrorCode == 0 {
+		e = NewError(e.Title, e.Detail, statusCode)
+	}
+	return e.Problem()
+}
+
+// errorStatus returns the HTTP status code for a response. Errors without a status are server errors.
+func errorStatus(statusCode int, err *ResponseError) int {
+	switch {
+	case statusCode != 0:
+		return statusCode
+	case err == nil:
+		return http.StatusOK
+	case err.Status != 0:
+		return err.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// writeResponse writes the response envelope. A response which only holds an error is written as a problem document.
+func writeResponse(w http.ResponseWriter, response Response[Pricing]) {
+	response.StatusCode = errorStatus(response.StatusCode, response.Error)
+	if response.Error != nil && response.ClaimStatus.IsEmpty() {
+		writeProblem(w, response.StatusCode, response.Error)
+		return
+	}
+	writeJSON(w, response.StatusCode, response)
+}
+
+// writeResponses writes the responses envelope. Responses which only hold an error are written as a problem document.
+func writeResponses(w http.ResponseWriter, responses ErrorAndResultResponses[Pricing]) {
+	responses.StatusCode = errorStatus(responses.StatusCode, responses.Error)
+	if responses.Error != nil && len(responses.Results) == 0 {
+		writeProblem(w, responses.StatusCode, responses.Error)
+		return
+	}
+	responses.SuccessCount, responses.ErrorCount = 0, 0
+	for _, result := range responses.Results {
+		if result.Error != nil {
+			responses.ErrorCount++
+		} else {
+			responses.SuccessCount++
+		}
+	}
+	writeJSON(w, responses.StatusCode, responses)
+}
+
+// writeProblem writes err as a problem document with the status code of the response it was returned in.
+func writeProblem(w http.ResponseWriter, statusCode int, err *ResponseError) {
+	problem := *err
+	problem.Status = statusCode
+	_ = WriteProblem(w, &problem)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		statusCode = http.StatusInternalServerError
+		data, _ = json.Marshal(Response[Pricing]{Error: &ResponseError{Title: "unable to encode response", Status: statusCode}, StatusCode: statusCode})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,180 @@
+package mph
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"braces.dev/errtrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handlerDoer sends client requests directly to a handler.
+type handlerDoer struct {
+	handler http.Handler
+}
+
+func (d handlerDoer) Do(req *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	d.handler.ServeHTTP(w, req)
+	return w.Result(), nil
+}
+
+// stubPricer prices claims at their billed amount and fails claims without a claim ID.
+type stubPricer struct {
+	configs []PriceConfig
+}
+
+func (p *stubPricer) priceClaim(claim Claim) ErrorAndResult[Pricing] {
+	if claim.ClaimID == "" {
+		return ErrorAndResult[Pricing]{Error: &ResponseError{Title: PriceErrorTitle, Detail: "claim ID is required"}}
+	}
+	return ErrorAndResult[Pricing]{Result: Pricing{ClaimID: claim.ClaimID, MedicareAmount: claim.BilledAmount}}
+}
+
+func (p *stubPricer) Price(ctx context.Context, config PriceConfig, input Claim) Response[Pricing] {
+	p.configs = append(p.configs, config)
+	result := p.priceClaim(input)
+	response := Response[Pricing]{Result: result.Result, Error: result.Error}
+	if result.Error != nil {
+		response.StatusCode = http.StatusUnprocessableEntity
+	}
+	return response
+}
+
+func (p *stubPricer) PriceBatch(ctx context.Context, config PriceConfig, inputs ...Claim) ErrorAndResultResponses[Pricing] {
+	p.configs = append(p.configs, config)
+	return p.EstimateClaims(ctx, inputs...)
+}
+
+func (p *stubPricer) EstimateClaims(ctx context.Context, inputs ...Claim) ErrorAndResultResponses[Pricing] {
+	var responses ErrorAndResultResponses[Pricing]
+	for _, input := range inputs {
+		responses.Results = append(responses.Results, p.priceClaim(input))
+	}
+	return responses
+}
+
+func (p *stubPricer) EstimateRateSheet(ctx context.Context, inputs ...RateSheet) ErrorAndResultResponses[Pricing] {
+	return ErrorAndResultResponses[Pricing]{Error: &ResponseError{Title: "not supported", Detail: "rate sheets are not supported"}, StatusCode: http.StatusNotImplemented}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pricer := &stubPricer{}
+	client := NewClient(handlerDoer{NewHandler(pricer)}, true, "key")
+
+	response := client.Price(ctx, PriceConfig{IncludeEdits: true}, Claim{ClaimID: "1", BilledAmount: 100})
+	require.Nil(t, response.Error)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, 100.0, response.Result.MedicareAmount)
+	assert.Equal(t, []PriceConfig{{IncludeEdits: true}}, pricer.configs)
+
+	response = client.Price(ctx, PriceConfig{}, Claim{})
+	require.NotNil(t, response.Error)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.ErrorIs(t, response.GetError(), ErrPricingUnavailable)
+
+	responses := client.PriceBatch(ctx, PriceConfig{}, Claim{ClaimID: "1", BilledAmount: 100}, Claim{}, Claim{ClaimID: "3", BilledAmount: 50})
+	require.Nil(t, responses.Error)
+	assert.Equal(t, http.StatusOK, responses.StatusCode)
+	assert.Equal(t, 2, responses.SuccessCount)
+	assert.Equal(t, 1, responses.ErrorCount)
+	require.Len(t, responses.Results, 3)
+	assert.Equal(t, "3", responses.Results[2].Result.ClaimID)
+	assert.Equal(t, "claim ID is required", responses.Results[1].Error.Detail)
+
+	responses = client.EstimateClaims(ctx, Claim{ClaimID: "1"})
+	assert.Equal(t, 1, responses.SuccessCount)
+
+	responses = client.EstimateRateSheet(ctx, RateSheet{})
+	require.NotNil(t, responses.Error)
+	assert.Equal(t, http.StatusNotImplemented, responses.StatusCode)
+	assert.Equal(t, http.StatusNotImplemented, responses.Error.Status)
+	assert.Equal(t, "rate sheets are not supported", responses.Error.Detail)
+}
+
+func TestHandlerInvalidRequest(t *testing.T) {
+	t.Parallel()
+	h := NewHandler(&stubPricer{})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/medicare/price/claims", strings.NewReader(`{"not":"an array"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"title":"invalid request"`)
+	assert.Contains(t, w.Body.String(), `"status":400`)
+	assert.NotContains(t, w.Body.String(), `"error"`)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/medicare/price/claim", strings.NewReader(`{}`))
+	req.Header.Set("use-drg-from-grouper", "true")
+	req.Header.Set("use-best-drg-price", "true")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "mutually exclusive")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/medicare/price/claim", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/medicare/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestToResponseError(t *testing.T) {
+	t.Parallel()
+	problem := toResponseError(InternalError("database unavailable", errtrace.Errorf("connection refused")), http.StatusBadRequest)
+	assert.Equal(t, &ResponseError{Title: "database unavailable", Detail: "Internal Server Error", Status: http.StatusInternalServerError}, problem)
+
+	problem = toResponseError(ClientError("bad claim", errtrace.Errorf("missing NPI")), http.StatusInternalServerError)
+	assert.Equal(t, &ResponseError{Title: "bad claim", Detail: "missing NPI", Status: http.StatusBadRequest}, problem)
+
+	problem = toResponseError(errtrace.Errorf("oops"), http.StatusBadRequest)
+	assert.Equal(t, &ResponseError{Title: invalidRequestTitle, Detail: "oops", Status: http.StatusBadRequest}, problem)
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+	var logs strings.Builder
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), logger)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/medicare/price/claim", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"internal server error","status":500,"detail":"Internal Server Error"}`, w.Body.String())
+	assert.Contains(t, logs.String(), "recovered from panic")
+	assert.Contains(t, logs.String(), "panic=boom")
+
+	// batch routes get the same problem document, which their envelope decodes as the error
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/medicare/price/claims", nil))
+	var responses ErrorAndResultResponses[Pricing]
+	require.NoError(t, Unmarshal(w.Body.Bytes(), &responses))
+	assert.Equal(t, http.StatusInternalServerError, responses.StatusCode)
+	assert.ErrorIs(t, responses.GetError(), ErrServerError)
+}
+
+func TestRecoverAfterWriteHeader(t *testing.T) {
+	t.Parallel()
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"results":[`))
+		panic("boom")
+	}), slog.New(slog.DiscardHandler))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/medicare/price/claims", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"results":[`, w.Body.String())
+}
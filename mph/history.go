@@ -0,0 +1,286 @@
+package mph
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// HistoryEventType identifies the kind of event recorded in a claim's history.
+type HistoryEventType string
+
+const (
+	HistoryEventTransition HistoryEventType = "transition" // the claim moved to a new status
+	HistoryEventPricing    HistoryEventType = "pricing"    // the claim was submitted for pricing
+	HistoryEventOverride   HistoryEventType = "override"   // a value was manually overridden
+)
+
+// HistoryEvent is a single entry in a claim's history. Exactly one of Transition, Pricing or Override is set
+// depending on the type of the event.
+type HistoryEvent struct {
+	ClaimID    string            `json:"claimID"`
+	Sequence   int               `json:"sequence"`            // Position of the event in the claim's history starting at 1
+	Time       time.Time         `json:"time"`                // When the event was recorded (UTC)
+	Type       HistoryEventType  `json:"type"`                // Kind of event
+	Actor      string            `json:"actor,omitzero"`      // User or system which recorded the event
+	Note       string            `json:"note,omitzero"`       // Free form note explaining the event
+	Transition *StatusTransition `json:"transition,omitzero"` // Supplied for transition events
+	Pricing    *PricingAttempt   `json:"pricing,omitzero"`    // Supplied for pricing events
+	Override   *Override         `json:"override,omitzero"`   // Supplied for override events
+}
+
+// StatusTransition records a claim moving from one status to another.
+type StatusTransition struct {
+	From ClaimStatus `json:"from,omitzero"` // Empty for the first status of a claim
+	To   ClaimStatus `json:"to"`
+}
+
+// PricingAttempt records a single request to price a claim and its outcome.
+type PricingAttempt struct {
+	Config      PriceConfig    `json:"config"`               // Configuration used for pricing
+	Fingerprint string         `json:"fingerprint"`          // Fingerprint of the claim which was priced (see Claim.Fingerprint)
+	Result      *Pricing       `json:"result,omitzero"`      // Pricing returned by the API
+	Error       *ResponseError `json:"error,omitzero"`       // Error returned by the API
+	ClaimStatus ClaimStatus    `json:"claimStatus,omitzero"` // The step the claim processing reached (for partial results only)
+}
+
+// Override records a manual change to a pricing value.
+type Override struct {
+	Field    string `json:"field"`             // Path of the overridden value using the same format as FieldChange (e.g. allowedAmount or services.2.allowedAmount)
+	OldValue any    `json:"oldValue,omitzero"` // Value before the override
+	NewValue any    `json:"newValue,omitzero"` // Value after the override
+	Reason   string `json:"reason"`            // Why the value was overridden (required)
+}
+
+// HistoryStore persists claim history events. Stores are append-only: events are never modified or removed once appended.
+type HistoryStore interface {
+	Append(ctx context.Context, event HistoryEvent) error             // Append adds an event to the end of the claim's history
+	Load(ctx context.Context, claimID string) ([]HistoryEvent, error) // Load returns the events for a claim in the order they were appended
+}
+
+// ClaimHistory is the audit trail of a single claim. It records every status the claim passed through, each
+// pricing attempt and any manual overrides. When a store is attached, each event is persisted before it is
+// added to the history.
+type ClaimHistory struct {
+	ClaimID string         `json:"claimID"`
+	Events  []HistoryEvent `json:"events"`
+
+	store HistoryStore
+	now   func() time.Time
+}
+
+// NewClaimHistory creates an empty history for a claim. The store may be nil to keep the history in memory only.
+func NewClaimHistory(claimID string, store HistoryStore) *ClaimHistory {
+	return &ClaimHistory{ClaimID: claimID, store: store}
+}
+
+// LoadClaimHistory loads the history of a claim from a store. New events are appended to the same store.
+func LoadClaimHistory(ctx context.Context, store HistoryStore, claimID string) (*ClaimHistory, error) {
+	events, err := store.Load(ctx, claimID)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	h := NewClaimHistory(claimID, store)
+	h.Events = events
+	if _, err := h.stateMachine(); err != nil {
+		return nil, errtrace.Errorf("history for claim %q is invalid: %w", claimID, err)
+	}
+	return h, nil
+}
+
+// Status returns the current status of the claim or an empty status if no transitions have been recorded.
+func (h *ClaimHistory) Status() ClaimStatus {
+	for i := len(h.Events) - 1; i >= 0; i-- {
+		if t := h.Events[i].Transition; t != nil {
+			return t.To
+		}
+	}
+	return ClaimStatus{}
+}
+
+// LastPricing returns the most recent pricing attempt.
+func (h *ClaimHistory) LastPricing() (PricingAttempt, bool) {
+	for i := len(h.Events) - 1; i >= 0; i-- {
+		if p := h.Events[i].Pricing; p != nil {
+			return *p, true
+		}
+	}
+	return PricingAttempt{}, false
+}
+
+// RecordTransition records the claim moving to a new status. The first transition may be to any status, after
+// which transitions must be allowed by ClaimStateMachine.
+func (h *ClaimHistory) RecordTransition(ctx context.Context, to ClaimStatus, actor, note string) error {
+	from := h.Status()
+	if !from.IsEmpty() {
+		m, err := h.stateMachine()
+		if err != nil {
+			return errtrace.Wrap(err)
+		}
+		if err := m.Transition(to); err != nil {
+			return errtrace.Wrap(err)
+		}
+	} else if !to.IsKnown() {
+		return errtrace.Wrap(&TransitionError{To: to, Err: ErrUnknownClaimStatus})
+	}
+	return errtrace.Wrap(h.append(ctx, HistoryEvent{
+		Type:       HistoryEventTransition,
+		Actor:      actor,
+		Note:       note,
+		Transition: &StatusTransition{From: from, To: to},
+	}))
+}
+
+// RecordPricing records an attempt to price the claim along with the configuration used and the result
+// returned by the API.
+func (h *ClaimHistory) RecordPricing(ctx context.Context, config PriceConfig, claim Claim, result ErrorAndResult[Pricing], actor string) error {
+	if claim.ClaimID != "" && claim.ClaimID != h.ClaimID {
+		return errtrace.Errorf("cannot record pricing of claim %q in the history of claim %q", claim.ClaimID, h.ClaimID)
+	}
+	fingerprint, err := claim.Fingerprint()
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	attempt := &PricingAttempt{
+		Config:      config,
+		Fingerprint: fingerprint,
+		Error:       result.Error,
+		ClaimStatus: result.ClaimStatus,
+	}
+	if result.Error == nil || !result.ClaimStatus.IsEmpty() {
+		attempt.Result = &result.Result
+	}
+	return errtrace.Wrap(h.append(ctx, HistoryEvent{Type: HistoryEventPricing, Actor: actor, Pricing: attempt}))
+}
+
+// RecordOverride records a manual override of a pricing value. A reason is required.
+func (h *ClaimHistory) RecordOverride(ctx context.Context, override Override, actor string) error {
+	if override.Field == "" {
+		return errtrace.Errorf("override field is required")
+	}
+	if override.Reason == "" {
+		return errtrace.Errorf("override of %s requires a reason", override.Field)
+	}
+	return errtrace.Wrap(h.append(ctx, HistoryEvent{Type: HistoryEventOverride, Actor: actor, Override: &override}))
+}
+
+func (h *ClaimHistory) append(ctx context.Context, event HistoryEvent) error {
+	now := time.Now
+	if h.now != nil {
+		now = h.now
+	}
+	event.ClaimID = h.ClaimID
+	event.Sequence = len(h.Events) + 1
+	event.Time = now().UTC()
+	if h.store != nil {
+		if err := h.store.Append(ctx, event); err != nil {
+			return errtrace.Wrap(err)
+		}
+	}
+	h.Events = append(h.Events, event)
+	return nil
+}
+
+// stateMachine replays the recorded transitions to rebuild the state of the claim.
+func (h *ClaimHistory) stateMachine() (*ClaimStateMachine, error) {
+	var m *ClaimStateMachine
+	for _, event := range h.Events {
+		if event.Transition == nil {
+			continue
+		}
+		if m == nil {
+			var err error
+			if m, err = NewClaimStateMachine(event.Transition.To); err != nil {
+				return nil, errtrace.Wrap(err)
+			}
+			continue
+		}
+		if err := m.Transition(event.Transition.To); err != nil {
+			return nil, errtrace.Errorf("event %d: %w", event.Sequence, err)
+		}
+	}
+	if m == nil {
+		return errtrace.Wrap2(NewClaimStateMachine(ClaimStatus{}))
+	}
+	return m, nil
+}
+
+// FileHistoryStore is a HistoryStore which appends events as JSON lines to a single file. It is safe for
+// concurrent use within a single process.
+type FileHistoryStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ HistoryStore = &FileHistoryStore{}
+
+// NewFileHistoryStore creates a store which appends to the file at path, creating it if needed.
+func NewFileHistoryStore(path string) *FileHistoryStore {
+	return &FileHistoryStore{path: path}
+}
+
+func (s *FileHistoryStore) Append(ctx context.Context, event HistoryEvent) error {
+	if err := ctx.Err(); err != nil {
+		return errtrace.Wrap(err)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errtrace.Wrap(err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errtrace.Wrap(err)
+	}
+	return errtrace.Wrap(f.Close())
+}
+
+func (s *FileHistoryStore) Load(ctx context.Context, claimID string) ([]HistoryEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	defer f.Close()
+
+	var events []HistoryEvent
+	decoder := jsontext.NewDecoder(f, decodeOptions())
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		var event HistoryEvent
+		err := json.UnmarshalDecode(decoder, &event)
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, errtrace.Errorf("reading %s: %w", s.path, err)
+		}
+		if event.ClaimID == claimID {
+			events = append(events, event)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mph
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := NewFileHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	h := NewClaimHistory("123", store)
+	h.now = func() time.Time { clock = clock.Add(time.Minute); return clock }
+	require.NoError(t, h.RecordTransition(ctx, StatusReceived, "sftp", "file claims.837"))
+	require.NoError(t, h.RecordTransition(ctx, StatusInputValidated, "", ""))
+
+	claim := Claim{ClaimID: "123", BilledAmount: 100}
+	config := PriceConfig{ContinueOnEditFail: true}
+	result := ErrorAndResult[Pricing]{Result: Pricing{ClaimID: "123", MedicareAmount: 50}}
+	require.NoError(t, h.RecordPricing(ctx, config, claim, result, "batch"))
+	require.NoError(t, h.RecordOverride(ctx, Override{Field: "allowedAmount", OldValue: 75.0, NewValue: 80.0, Reason: "single case agreement"}, "jane"))
+
+	err := h.RecordTransition(ctx, StatusNew, "", "")
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Error(t, h.RecordOverride(ctx, Override{Field: "allowedAmount"}, "jane"), "reason is required")
+	assert.Error(t, h.RecordPricing(ctx, config, Claim{ClaimID: "456"}, result, ""), "claim must match")
+	require.Len(t, h.Events, 4)
+
+	assert.Equal(t, StatusInputValidated, h.Status())
+	assert.Equal(t, StatusReceived, h.Events[1].Transition.From)
+	assert.Equal(t, 4, h.Events[3].Sequence)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 3, 0, 0, time.UTC), h.Events[2].Time)
+	attempt, ok := h.LastPricing()
+	require.True(t, ok)
+	fingerprint, err := claim.Fingerprint()
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, attempt.Fingerprint)
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, config, attempt.Config)
+	assert.Equal(t, 50.0, attempt.Result.MedicareAmount)
+
+	// events for other claims are stored in the same file but not loaded
+	other := NewClaimHistory("456", store)
+	require.NoError(t, other.RecordTransition(ctx, StatusNew, "", ""))
+
+	loaded, err := LoadClaimHistory(ctx, store, "123")
+	require.NoError(t, err)
+	assert.Equal(t, h.Events, loaded.Events)
+	require.NoError(t, loaded.RecordTransition(ctx, StatusProviderMatched, "", ""))
+	loaded, err = LoadClaimHistory(ctx, store, "123")
+	require.NoError(t, err)
+	assert.Len(t, loaded.Events, 5)
+	assert.Equal(t, StatusProviderMatched, loaded.Status())
+
+	data, err := json.Marshal(h)
+	require.NoError(t, err)
+	var decoded ClaimHistory
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, h.Events, decoded.Events)
+}
+
+func TestClaimHistoryHeld(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	h := NewClaimHistory("123", nil)
+	for _, to := range []ClaimStatus{StatusEditComplete, StatusHeld, StatusEditComplete, StatusMedicarePriced} {
+		require.NoError(t, h.RecordTransition(ctx, to, "", ""))
+	}
+	assert.Equal(t, StatusMedicarePriced, h.Status())
+}
+
+func TestFileHistoryStoreMissingFile(t *testing.T) {
+	t.Parallel()
+	store := NewFileHistoryStore(filepath.Join(t.TempDir(), "missing.jsonl"))
+	events, err := store.Load(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,73 @@
+package mph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Call describes a single request made by the Client. Interceptors may modify the header and body before the
+// request is sent (e.g. to add tracing headers or a signature).
+type Call struct {
+	Path   string      // API path (e.g. /v1/medicare/price/claim)
+	Config PriceConfig // Configuration for the request (empty for estimates)
+	Input  any         // Claim, []Claim or []RateSheet being sent
+	Count  int         // Number of claims or rate sheets being sent
+	Body   []byte      // JSON encoded request body
+	Header http.Header // Headers added to the request
+}
+
+// Outcome describes the result of a Call.
+type Outcome struct {
+	StatusCode int           // HTTP status code of the response (0 if no response was received)
+	Duration   time.Duration // Time taken to send the request and decode the response
+	Response   any           // Decoded envelope: *Response[Pricing] for Price, otherwise *ErrorAndResultResponses[Pricing]
+	Err        error         // Error sending the request or decoding the response
+}
+
+// Interceptor is called around every request made by the Client. Before is called in the order the interceptors
+// were added and may return a new context for the request or an error to stop the request from being sent.
+// After is called in reverse order, for each interceptor whose Before succeeded, once the response is decoded.
+type Interceptor interface {
+	Before(ctx context.Context, call *Call) (context.Context, error)
+	After(ctx context.Context, call *Call, outcome Outcome)
+}
+
+// InterceptorFuncs implements Interceptor using functions. Either function may be nil.
+type InterceptorFuncs struct {
+	BeforeFunc func(ctx context.Context, call *Call) (context.Context, error)
+	AfterFunc  func(ctx context.Context, call *Call, outcome Outcome)
+}
+
+var _ Interceptor = InterceptorFuncs{}
+
+func (f InterceptorFuncs) Before(ctx context.Context, call *Call) (context.Context, error) {
+	if f.BeforeFunc == nil {
+		return ctx, nil
+	}
+	return f.BeforeFunc(ctx, call)
+}
+
+func (f InterceptorFuncs) After(ctx context.Context, call *Call, outcome Outcome) {
+	if f.AfterFunc != nil {
+		f.AfterFunc(ctx, call, outcome)
+	}
+}
+
+// Use adds interceptors which are called around every request. Use is not safe to call concurrently with requests.
+func (c *Client) Use(interceptors ...Interceptor) *Client {
+	c.interceptors = append(c.interceptors, interceptors...)
+	return c
+}
+
+// interceptorError converts an error returned by an interceptor into a ResponseError. An *Error (e.g. a 429 from a
+// quota interceptor) keeps its title and status code.
+func interceptorError(path string, err error) *ResponseError {
+	var e *Error
+	if errors.As(err, &e) && e.Detail != nil {
+		return &ResponseError{Title: e.Title, Detail: e.Detail.Error(), Status: e.ErrorCode}
+	}
+	return &ResponseError{Title: fmt.Sprintf("request to %s was stopped", path), Detail: err.Error()}
+}
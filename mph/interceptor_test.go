@@ -0,0 +1,134 @@
+package mph
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"braces.dev/errtrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey string
+
+// recordingInterceptor records the order it was called in and adds a header to each request.
+func recordingInterceptor(t *testing.T, name string, calls *[]string) Interceptor {
+	return InterceptorFuncs{
+		BeforeFunc: func(ctx context.Context, call *Call) (context.Context, error) {
+			*calls = append(*calls, "before "+name)
+			call.Header.Add("x-interceptor", name)
+			return context.WithValue(ctx, contextKey(name), true), nil
+		},
+		AfterFunc: func(ctx context.Context, call *Call, outcome Outcome) {
+			assert.Equal(t, true, ctx.Value(contextKey(name)))
+			*calls = append(*calls, "after "+name)
+		},
+	}
+}
+
+func TestClientInterceptors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var calls []string
+	var outcomes []Outcome
+	var paths []string
+	headers := http.Header{}
+	server := NewHandler(&stubPricer{})
+	client := NewClient(handlerDoer{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		server.ServeHTTP(w, r)
+	})}, true, "key")
+	client.Use(recordingInterceptor(t, "first", &calls), recordingInterceptor(t, "second", &calls))
+	client.Use(InterceptorFuncs{
+		BeforeFunc: func(ctx context.Context, call *Call) (context.Context, error) {
+			paths = append(paths, call.Path)
+			assert.Equal(t, "true", call.Header.Get("include-edits"))
+			assert.Equal(t, 1, call.Count)
+			assert.JSONEq(t, `{"claimID":"1","billedAmount":100}`, string(call.Body))
+			return ctx, nil
+		},
+		AfterFunc: func(ctx context.Context, call *Call, outcome Outcome) {
+			outcomes = append(outcomes, outcome)
+		},
+	})
+
+	response := client.Price(ctx, PriceConfig{IncludeEdits: true}, Claim{ClaimID: "1", BilledAmount: 100})
+	require.Nil(t, response.Error)
+	assert.Equal(t, []string{"before first", "before second", "after second", "after first"}, calls)
+	assert.Equal(t, []string{"first", "second"}, headers.Values("x-interceptor"))
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, http.StatusOK, outcomes[0].StatusCode)
+	assert.Positive(t, outcomes[0].Duration)
+	assert.NoError(t, outcomes[0].Err)
+	decoded, ok := outcomes[0].Response.(*Response[Pricing])
+	require.True(t, ok)
+	assert.Equal(t, 100.0, decoded.Result.MedicareAmount)
+	assert.Equal(t, []string{"/v1/medicare/price/claim"}, paths)
+}
+
+func TestClientInterceptorAllMethods(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var paths []string
+	client := NewClient(handlerDoer{NewHandler(&stubPricer{})}, true, "key")
+	client.Use(InterceptorFuncs{AfterFunc: func(ctx context.Context, call *Call, outcome Outcome) {
+		paths = append(paths, call.Path)
+		if call.Path != "/v1/medicare/price/claim" {
+			_, ok := outcome.Response.(*ErrorAndResultResponses[Pricing])
+			assert.True(t, ok, call.Path)
+		}
+	}})
+	client.Price(ctx, PriceConfig{}, Claim{ClaimID: "1"})
+	client.PriceBatch(ctx, PriceConfig{}, Claim{ClaimID: "1"})
+	client.EstimateClaims(ctx, Claim{ClaimID: "1"})
+	client.EstimateRateSheet(ctx, RateSheet{})
+	assert.Equal(t, []string{"/v1/medicare/price/claim", "/v1/medicare/price/claims", "/v1/medicare/estimate/claims", "/v1/medicare/estimate/rate-sheet"}, paths)
+}
+
+func TestClientInterceptorStopsRequest(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	doer := &fakeDoer{}
+	var calls []string
+	client := NewClient(doer, true, "key")
+	client.Use(recordingInterceptor(t, "first", &calls), InterceptorFuncs{
+		BeforeFunc: func(ctx context.Context, call *Call) (context.Context, error) {
+			return ctx, NewError("quota exceeded", errtrace.Errorf("tenant has priced %d claims today", 1000), http.StatusTooManyRequests)
+		},
+		AfterFunc: func(ctx context.Context, call *Call, outcome Outcome) {
+			calls = append(calls, "after quota")
+		},
+	}, recordingInterceptor(t, "last", &calls))
+
+	responses := client.PriceBatch(ctx, PriceConfig{}, Claim{}, Claim{})
+	assert.Empty(t, doer.RequestsMade)
+	assert.Equal(t, []string{"before first", "after first"}, calls)
+	require.NotNil(t, responses.Error)
+	assert.Equal(t, http.StatusTooManyRequests, responses.StatusCode)
+	assert.Equal(t, 2, responses.ErrorCount)
+	assert.ErrorIs(t, responses.GetError(), ErrRateLimited)
+
+	client = NewClient(doer, true, "key").Use(InterceptorFuncs{BeforeFunc: func(ctx context.Context, call *Call) (context.Context, error) {
+		return ctx, errtrace.Errorf("not signed")
+	}})
+	response := client.Price(ctx, PriceConfig{}, Claim{})
+	assert.Equal(t, &ResponseError{Title: "request to /v1/medicare/price/claim was stopped", Detail: "not signed"}, response.Error)
+}
+
+func TestClientInterceptorStopsRequestWithoutContext(t *testing.T) {
+	t.Parallel()
+	doer := &fakeDoer{}
+	var calls []string
+	client := NewClient(doer, true, "key").Use(recordingInterceptor(t, "first", &calls), InterceptorFuncs{
+		BeforeFunc: func(ctx context.Context, call *Call) (context.Context, error) {
+			return nil, errtrace.Errorf("not signed")
+		},
+	})
+
+	// the context of the first interceptor is kept when the second returns a nil context with its error
+	response := client.Price(context.Background(), PriceConfig{}, Claim{})
+	assert.Empty(t, doer.RequestsMade)
+	assert.Equal(t, []string{"before first", "after first"}, calls)
+	assert.Equal(t, "not signed", response.Error.Detail)
+}
@@ -0,0 +1,274 @@
+package mph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// SetLogger sets the logger used to log the lifecycle of every request. A nil logger (the default) disables logging.
+// SetLogger is not safe to call concurrently with requests.
+func (c *Client) SetLogger(logger *slog.Logger) *Client {
+	c.logger = logger
+	return c
+}
+
+// SetVerboseLogging sets whether the client's logs include protected health information, such as the details of
+// problem documents. It should only be enabled when the logs are handled as PHI. Errors recorded on spans are always
+// redacted. SetVerboseLogging is not safe to call concurrently with requests.
+func (c *Client) SetVerboseLogging(verbose bool) *Client {
+	c.verbose = verbose
+	return c
+}
+
+// logValue wraps v in Verbose when verbose logging is enabled.
+func (c *Client) logValue(v any) any {
+	if c.verbose {
+		return Verbose{v}
+	}
+	return v
+}
+
+// logStart logs that a call is about to be sent.
+func (c *Client) logStart(ctx context.Context, call *Call) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.DebugContext(ctx, "sending request", "path", call.Path, "count", call.Count)
+}
+
+// logOutcome logs the outcome of a call. Requests which fail entirely are logged as warnings (client errors) or
+// errors (server errors), and each claim which fails is logged at debug level.
+func (c *Client) logOutcome(ctx context.Context, call *Call, outcome Outcome, summary outcomeSummary) {
+	if c.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("path", call.Path),
+		slog.Int("status", outcome.StatusCode),
+		slog.Duration("duration", outcome.Duration),
+		slog.Int("successCount", summary.successes),
+		slog.Int("errorCount", summary.failures),
+	}
+	if summary.err != nil {
+		level := slog.LevelError
+		if outcome.StatusCode >= http.StatusBadRequest && outcome.StatusCode < http.StatusInternalServerError {
+			level = slog.LevelWarn
+		}
+		c.logger.LogAttrs(ctx, level, "request failed", append(attrs, slog.Any("error", c.logValue(summary.err)))...)
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelInfo, "request completed", attrs...)
+	for i, result := range summary.results {
+		if result.Error != nil {
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "claim failed", slog.String("path", call.Path), slog.Int("index", i), slog.Any("error", c.logValue(result.Error)))
+		}
+	}
+}
+
+var (
+	_ slog.LogValuer = Claim{}
+	_ slog.LogValuer = Service{}
+	_ slog.LogValuer = Provider{}
+	_ slog.LogValuer = Pricing{}
+	_ slog.LogValuer = ResponseError{}
+	_ slog.LogValuer = Verbose{}
+)
+
+// Verbose logs its value including protected health information. By default the LogValue methods of Claim, Service,
+// Provider, Pricing and ResponseError only include identifiers, counts, amounts and codes. Wrapping one of them (or a
+// pointer to one) in Verbose also logs patient details, dates, names, addresses and error details. It should only
+// be used when logs are handled as PHI.
+type Verbose struct {
+	Value any // Value to log
+}
+
+// verboseLogValuer is implemented by the types which Verbose can log.
+type verboseLogValuer interface {
+	logValue(verbose bool) slog.Value
+}
+
+// LogValue logs the value verbosely when it supports it, or as it would be logged otherwise.
+func (v Verbose) LogValue() slog.Value {
+	if value, ok := v.Value.(verboseLogValuer); ok {
+		return value.logValue(true)
+	}
+	return slog.AnyValue(v.Value)
+}
+
+// LogValue logs the claim without protected health information.
+func (c Claim) LogValue() slog.Value {
+	return c.logValue(false)
+}
+
+func (c Claim) logValue(verbose bool) slog.Value {
+	diagnosisCount := len(c.OtherDiagnoses)
+	if c.PrincipalDiagnosis != nil {
+		diagnosisCount++
+	}
+	attrs := []slog.Attr{
+		slog.String("claimID", c.ClaimID),
+		slog.String("formType", string(c.FormType)),
+		slog.String("billTypeOrPOS", c.BillTypeOrPOS),
+		slog.String("drg", c.DRG),
+		slog.Float64("billedAmount", c.BilledAmount),
+		slog.Float64("allowedAmount", c.AllowedAmount),
+		slog.Float64("paidAmount", c.PaidAmount),
+		slog.Int("serviceCount", len(c.Services)),
+		slog.Int("diagnosisCount", diagnosisCount),
+		slog.Any("provider", c.Provider.logValue(verbose)),
+	}
+	if verbose {
+		var dateOfBirth string
+		if c.PatientDateOfBirth != nil {
+			dateOfBirth = c.PatientDateOfBirth.String()
+		}
+		attrs = append(attrs,
+			slog.String("planCode", c.PlanCode),
+			slog.Int("patientSex", int(c.PatientSex)),
+			slog.String("patientDateOfBirth", dateOfBirth),
+			slog.String("dateFrom", c.DateFrom.String()),
+			slog.String("dateThrough", c.DateThrough.String()),
+			slog.String("ambulancePickupZIP", c.AmbulancePickupZIP),
+			slog.String("dischargeStatus", c.DischargeStatus),
+			slog.String("admitDiagnosis", c.AdmitDiagnosis),
+			slog.Any("principalDiagnosis", c.PrincipalDiagnosis),
+			slog.Any("otherDiagnoses", c.OtherDiagnoses),
+			slog.Any("services", c.Services),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue logs the service without protected health information.
+func (s Service) LogValue() slog.Value {
+	return s.logValue(false)
+}
+
+func (s Service) logValue(verbose bool) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("lineNumber", s.LineNumber),
+		slog.String("revCode", s.RevCode),
+		slog.String("procedureCode", s.ProcedureCode),
+		slog.Any("procedureModifiers", s.ProcedureModifiers),
+		slog.String("placeOfService", s.PlaceOfService),
+		slog.Float64("quantity", s.Quantity),
+		slog.String("units", s.Units),
+		slog.Float64("billedAmount", s.BilledAmount),
+		slog.Float64("allowedAmount", s.AllowedAmount),
+		slog.Float64("paidAmount", s.PaidAmount),
+	}
+	if verbose {
+		attrs = append(attrs,
+			slog.String("drugCode", s.DrugCode),
+			slog.String("dateFrom", s.DateFrom.String()),
+			slog.String("dateThrough", s.DateThrough.String()),
+			slog.String("ambulancePickupZIP", s.AmbulancePickupZIP),
+			slog.Any("provider", s.Provider.logValue(verbose)),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue logs the provider's identifiers. Contact details are only logged by Verbose.
+func (p Provider) LogValue() slog.Value {
+	return p.logValue(false)
+}
+
+func (p Provider) logValue(verbose bool) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("npi", p.NPI),
+		slog.String("ccn", p.CCN),
+		slog.String("providerTaxonomy", p.ProviderTaxonomy),
+		slog.String("providerState", p.ProviderState),
+	}
+	if verbose {
+		attrs = append(attrs,
+			slog.String("providerTaxID", p.ProviderTaxID),
+			slog.String("providerFirstName", p.ProviderFirstName),
+			slog.String("providerLastName", p.ProviderLastName),
+			slog.String("providerOrgName", p.ProviderOrgName),
+			slog.String("providerAddress1", p.ProviderAddress1),
+			slog.String("providerCity", p.ProviderCity),
+			slog.String("providerZIP", p.ProviderZIP),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue logs the pricing without notes or error details.
+func (p Pricing) LogValue() slog.Value {
+	return p.logValue(false)
+}
+
+func (p Pricing) logValue(verbose bool) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("claimID", p.ClaimID),
+		slog.Float64("medicareAmount", p.MedicareAmount),
+		slog.Float64("allowedAmount", p.AllowedAmount),
+		slog.String("medicareRepricingCode", string(p.MedicareRepricingCode)),
+		slog.String("allowedRepricingCode", string(p.AllowedRepricingCode)),
+		slog.String("medicareSource", string(p.MedicareSource)),
+		slog.String("networkCode", p.NetworkCode),
+		slog.Int("serviceCount", len(p.Services)),
+	}
+	if p.EditError != nil {
+		attrs = append(attrs, slog.Any("editError", p.EditError.logValue(verbose)))
+	}
+	if verbose {
+		attrs = append(attrs,
+			slog.String("medicareRepricingNote", p.MedicareRepricingNote),
+			slog.String("allowedRepricingNote", p.AllowedRepricingNote),
+			slog.String("pricerResult", p.PricerResult),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue logs the error's type, title and status. The detail may describe the claim, so it is only logged by
+// Verbose.
+func (r ResponseError) LogValue() slog.Value {
+	return r.logValue(false)
+}
+
+func (r ResponseError) logValue(verbose bool) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("type", r.Type),
+		slog.String("title", r.Title),
+		slog.Int("status", r.Status),
+		slog.Int("errorCount", len(r.Errors)),
+	}
+	if verbose {
+		attrs = append(attrs,
+			slog.String("detail", r.Detail),
+			slog.String("instance", r.Instance),
+			slog.Any("errors", r.Errors),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// RedactError returns an error which is safe to record in traces. A *ResponseError in err is reduced to its title
+// and status, which LogValue also logs. Other errors are returned unchanged.
+func RedactError(err error) error {
+	var r *ResponseError
+	if !errors.As(err, &r) {
+		return err
+	}
+	return redactedError{title: r.Title, status: r.Status}
+}
+
+// redactedError is a ResponseError without its detail.
+type redactedError struct {
+	title  string
+	status int
+}
+
+func (e redactedError) Error() string {
+	if e.status == 0 {
+		return e.title
+	}
+	return fmt.Sprintf("%s (status %d)", e.title, e.status)
+}
@@ -0,0 +1,136 @@
+package mph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var logClaim = Claim{
+	Provider:           Provider{NPI: "1234567890", ProviderFirstName: "Jane", ProviderAddress1: "1 Main St", ProviderState: "TX"},
+	ClaimID:            "claim-1",
+	PatientDateOfBirth: NewDatePtr(1980, 1, 2),
+	PatientSex:         SexTypeFemale,
+	AmbulancePickupZIP: "75001",
+	FormType:           UBFormType,
+	BilledAmount:       100,
+	DateFrom:           NewDate(2024, 3, 4),
+	PrincipalDiagnosis: &Diagnosis{Code: "I10"},
+	Services:           []Service{{LineNumber: "1", ProcedureCode: "99213", DateFrom: NewDate(2024, 3, 4), BilledAmount: 100}},
+}
+
+// logJSON logs v using a JSON handler and returns the decoded value.
+func logJSON(t *testing.T, v any) map[string]any {
+	var buf strings.Builder
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("test", "v", v)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &record))
+	value, ok := record["v"].(map[string]any)
+	require.True(t, ok, buf.String())
+	return value
+}
+
+func TestLogValue(t *testing.T) {
+	t.Parallel()
+	claim := logJSON(t, logClaim)
+	assert.Equal(t, "claim-1", claim["claimID"])
+	assert.Equal(t, 100.0, claim["billedAmount"])
+	assert.Equal(t, 1.0, claim["serviceCount"])
+	assert.Equal(t, 1.0, claim["diagnosisCount"])
+	assert.Equal(t, map[string]any{"npi": "1234567890", "ccn": "", "providerTaxonomy": "", "providerState": "TX"}, claim["provider"])
+	assert.NotContains(t, claim, "patientDateOfBirth")
+	assert.NotContains(t, claim, "ambulancePickupZIP")
+	assert.NotContains(t, claim, "services")
+
+	service := logJSON(t, logClaim.Services[0])
+	assert.Equal(t, "99213", service["procedureCode"])
+	assert.NotContains(t, service, "dateFrom")
+
+	pricing := logJSON(t, Pricing{ClaimID: "claim-1", MedicareAmount: 50, MedicareSource: MedicareSourceInpatient, MedicareRepricingNote: "note",
+		EditError: &ResponseError{Title: "edit failed", Detail: "patient is 44 years old"}})
+	assert.Equal(t, 50.0, pricing["medicareAmount"])
+	assert.Equal(t, string(MedicareSourceInpatient), pricing["medicareSource"])
+	assert.NotContains(t, pricing, "medicareRepricingNote")
+	assert.Equal(t, map[string]any{"type": "", "title": "edit failed", "status": 0.0, "errorCount": 0.0}, pricing["editError"])
+
+	problem := logJSON(t, &ResponseError{Title: "invalid claim", Status: 400, Detail: "patient Jane Doe", Errors: []ValidationError{{Pointer: "/npi"}}})
+	assert.Equal(t, map[string]any{"type": "", "title": "invalid claim", "status": 400.0, "errorCount": 1.0}, problem)
+}
+
+func TestLogValueVerbose(t *testing.T) {
+	t.Parallel()
+	claim := logJSON(t, Verbose{logClaim})
+	assert.Equal(t, "19800102", claim["patientDateOfBirth"])
+	assert.Equal(t, "20240304", claim["dateFrom"])
+	assert.Equal(t, "75001", claim["ambulancePickupZIP"])
+	provider, ok := claim["provider"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "Jane", provider["providerFirstName"])
+	assert.Equal(t, "1 Main St", provider["providerAddress1"])
+
+	claim = logJSON(t, Verbose{&Claim{ClaimID: "claim-2"}})
+	assert.Empty(t, claim["patientDateOfBirth"])
+
+	pricing := logJSON(t, Verbose{Pricing{MedicareRepricingNote: "note", EditError: &ResponseError{Detail: "patient is 44 years old"}}})
+	assert.Equal(t, "note", pricing["medicareRepricingNote"])
+	editError, ok := pricing["editError"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "patient is 44 years old", editError["detail"])
+
+	problem := logJSON(t, Verbose{ResponseError{Title: "invalid claim", Detail: "patient Jane Doe"}})
+	assert.Equal(t, "patient Jane Doe", problem["detail"])
+
+	other := logJSON(t, Verbose{map[string]any{"key": "value"}})
+	assert.Equal(t, map[string]any{"key": "value"}, other)
+}
+
+func TestRedactError(t *testing.T) {
+	t.Parallel()
+	problem := &ResponseError{Title: "invalid claim", Status: http.StatusBadRequest, Detail: "patient Jane Doe"}
+	assert.EqualError(t, RedactError(problem), "invalid claim (status 400)")
+	assert.EqualError(t, RedactError(problem.toError(0)), "invalid claim (status 400)")
+	assert.EqualError(t, RedactError(&ResponseError{Title: "invalid claim", Detail: "patient Jane Doe"}), "invalid claim")
+	assert.EqualError(t, RedactError(errors.New("timeout")), "timeout")
+}
+
+func TestClientLogger(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(handlerDoer{NewHandler(&stubPricer{})}, true, "key").SetLogger(logger)
+
+	client.PriceBatch(ctx, PriceConfig{}, Claim{ClaimID: "1", BilledAmount: 100}, Claim{})
+	logs := buf.String()
+	assert.Contains(t, logs, `level=DEBUG msg="sending request" path=/v1/medicare/price/claims count=2`)
+	assert.Contains(t, logs, `level=INFO msg="request completed" path=/v1/medicare/price/claims status=200`)
+	assert.Contains(t, logs, "successCount=1 errorCount=1")
+	assert.Contains(t, logs, `level=DEBUG msg="claim failed" path=/v1/medicare/price/claims index=1 error.type="" error.title="pricing not available"`)
+	assert.NotContains(t, logs, "claim ID is required")
+
+	buf.Reset()
+	client.EstimateRateSheet(ctx, RateSheet{})
+	assert.Contains(t, buf.String(), `level=ERROR msg="request failed" path=/v1/medicare/estimate/rate-sheet status=501`)
+	assert.Contains(t, buf.String(), `error.title="not supported"`)
+
+	buf.Reset()
+	client.Price(ctx, PriceConfig{}, Claim{})
+	assert.Contains(t, buf.String(), `level=WARN msg="request failed" path=/v1/medicare/price/claim status=422`)
+}
+
+func TestClientVerboseLogging(t *testing.T) {
+	t.Parallel()
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(handlerDoer{NewHandler(&stubPricer{})}, true, "key").SetLogger(logger).SetVerboseLogging(true)
+
+	client.PriceBatch(context.Background(), PriceConfig{}, Claim{ClaimID: "1", BilledAmount: 100}, Claim{})
+	assert.Contains(t, buf.String(), "claim ID is required")
+}
@@ -0,0 +1,141 @@
+package mph
+
+import (
+	"github.com/mypricehealth/decimal"
+)
+
+// Money returns amount as an exact decimal. The shortest decimal which converts back to the same float64 is used, so
+// an amount decoded from 123.45 is exactly 123.45 rather than the nearest binary fraction. Sums, differences and
+// comparisons of the result are exact.
+func Money(amount float64) decimal.Decimal {
+	return decimal.NewFromFloat(amount)
+}
+
+// SumMoney returns the exact sum of amounts.
+func SumMoney(amounts ...float64) decimal.Decimal {
+	total := decimal.Zero
+	for _, amount := range amounts {
+		total = total.Add(Money(amount))
+	}
+	return total
+}
+
+// moneyFloat converts an amount back to a float64. Amounts created with Money convert back to the original float64.
+func moneyFloat(amount decimal.Decimal) float64 {
+	return amount.InexactFloat64()
+}
+
+// ClaimAmounts is an exact decimal view of the amounts on a Claim.
+type ClaimAmounts struct {
+	BilledAmount  decimal.Decimal  `json:"billedAmount,omitzero"`  // Billed amount from provider
+	AllowedAmount decimal.Decimal  `json:"allowedAmount,omitzero"` // Amount allowed by the plan for payment
+	PaidAmount    decimal.Decimal  `json:"paidAmount,omitzero"`    // Amount paid by the plan for the claim
+	Services      []ServiceAmounts `json:"services,omitzero"`      // Amounts for each service, in the same order as the claim
+}
+
+// ServiceAmounts is an exact decimal view of the amounts on a Service.
+type ServiceAmounts struct {
+	LineNumber    string          `json:"lineNumber,omitzero"`    // Line number of the service
+	BilledAmount  decimal.Decimal `json:"billedAmount,omitzero"`  // Billed charge for the service
+	AllowedAmount decimal.Decimal `json:"allowedAmount,omitzero"` // Plan allowed amount for the service
+	PaidAmount    decimal.Decimal `json:"paidAmount,omitzero"`    // Plan paid amount for the service
+}
+
+// Amounts returns the amounts of the claim and its services as exact decimals.
+func (c Claim) Amounts() ClaimAmounts {
+	a := ClaimAmounts{BilledAmount: Money(c.BilledAmount), AllowedAmount: Money(c.AllowedAmount), PaidAmount: Money(c.PaidAmount)}
+	if c.Services != nil {
+		a.Services = make([]ServiceAmounts, len(c.Services))
+	}
+	for i, s := range c.Services {
+		a.Services[i] = ServiceAmounts{LineNumber: s.LineNumber, BilledAmount: Money(s.BilledAmount), AllowedAmount: Money(s.AllowedAmount), PaidAmount: Money(s.PaidAmount)}
+	}
+	return a
+}
+
+// WithAmounts returns a copy of the claim with its amounts set from a. Services are matched by position and services
+// without amounts in a are unchanged.
+func (c Claim) WithAmounts(a ClaimAmounts) Claim {
+	c.BilledAmount = moneyFloat(a.BilledAmount)
+	c.AllowedAmount = moneyFloat(a.AllowedAmount)
+	c.PaidAmount = moneyFloat(a.PaidAmount)
+	c.Services = append([]Service(nil), c.Services...)
+	for i := range min(len(c.Services), len(a.Services)) {
+		c.Services[i].BilledAmount = moneyFloat(a.Services[i].BilledAmount)
+		c.Services[i].AllowedAmount = moneyFloat(a.Services[i].AllowedAmount)
+		c.Services[i].PaidAmount = moneyFloat(a.Services[i].PaidAmount)
+	}
+	return c
+}
+
+// ServiceTotals returns the exact sum of the amounts of the services.
+func (a ClaimAmounts) ServiceTotals() ServiceAmounts {
+	var total ServiceAmounts
+	for _, s := range a.Services {
+		total.BilledAmount = total.BilledAmount.Add(s.BilledAmount)
+		total.AllowedAmount = total.AllowedAmount.Add(s.AllowedAmount)
+		total.PaidAmount = total.PaidAmount.Add(s.PaidAmount)
+	}
+	return total
+}
+
+// PricingAmounts is an exact decimal view of the amounts on a Pricing.
+type PricingAmounts struct {
+	MedicareAmount decimal.Decimal        `json:"medicareAmount,omitzero"` // The amount Medicare would pay for the claim
+	AllowedAmount  decimal.Decimal        `json:"allowedAmount,omitzero"`  // The allowed amount based on a contract or RBP pricing
+	Services       []PricedServiceAmounts `json:"services,omitzero"`       // Amounts for each priced service, in the same order as the pricing
+}
+
+// PricedServiceAmounts is an exact decimal view of the amounts on a PricedService.
+type PricedServiceAmounts struct {
+	LineNumber                  string          `json:"lineNumber,omitzero"`     // Line number of the service
+	MedicareAmount              decimal.Decimal `json:"medicareAmount,omitzero"` // Amount Medicare would pay for the service
+	AllowedAmount               decimal.Decimal `json:"allowedAmount,omitzero"`  // Allowed amount based on a contract or RBP pricing
+	TechnicalComponentAmount    decimal.Decimal `json:"tcAmount,omitzero"`       // Amount Medicare would pay for the technical component
+	ProfessionalComponentAmount decimal.Decimal `json:"pcAmount,omitzero"`       // Amount Medicare would pay for the professional component
+}
+
+// Amounts returns the amounts of the pricing and its services as exact decimals.
+func (p Pricing) Amounts() PricingAmounts {
+	a := PricingAmounts{MedicareAmount: Money(p.MedicareAmount), AllowedAmount: Money(p.AllowedAmount)}
+	if p.Services != nil {
+		a.Services = make([]PricedServiceAmounts, len(p.Services))
+	}
+	for i, s := range p.Services {
+		a.Services[i] = PricedServiceAmounts{
+			LineNumber:                  s.LineNumber,
+			MedicareAmount:              Money(s.MedicareAmount),
+			AllowedAmount:               Money(s.AllowedAmount),
+			TechnicalComponentAmount:    Money(s.TechnicalComponentAmount),
+			ProfessionalComponentAmount: Money(s.ProfessionalComponentAmount),
+		}
+	}
+	return a
+}
+
+// WithAmounts returns a copy of the pricing with its amounts set from a. Services are matched by position and
+// services without amounts in a are unchanged.
+func (p Pricing) WithAmounts(a PricingAmounts) Pricing {
+	p.MedicareAmount = moneyFloat(a.MedicareAmount)
+	p.AllowedAmount = moneyFloat(a.AllowedAmount)
+	p.Services = append([]PricedService(nil), p.Services...)
+	for i := range min(len(p.Services), len(a.Services)) {
+		p.Services[i].MedicareAmount = moneyFloat(a.Services[i].MedicareAmount)
+		p.Services[i].AllowedAmount = moneyFloat(a.Services[i].AllowedAmount)
+		p.Services[i].TechnicalComponentAmount = moneyFloat(a.Services[i].TechnicalComponentAmount)
+		p.Services[i].ProfessionalComponentAmount = moneyFloat(a.Services[i].ProfessionalComponentAmount)
+	}
+	return p
+}
+
+// ServiceTotals returns the exact sum of the amounts of the priced services.
+func (a PricingAmounts) ServiceTotals() PricedServiceAmounts {
+	var total PricedServiceAmounts
+	for _, s := range a.Services {
+		total.MedicareAmount = total.MedicareAmount.Add(s.MedicareAmount)
+		total.AllowedAmount = total.AllowedAmount.Add(s.AllowedAmount)
+		total.TechnicalComponentAmount = total.TechnicalComponentAmount.Add(s.TechnicalComponentAmount)
+		total.ProfessionalComponentAmount = total.ProfessionalComponentAmount.Add(s.ProfessionalComponentAmount)
+	}
+	return total
+}
@@ -0,0 +1,66 @@
+package mph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "123.45", Money(123.45).String())
+	assert.Equal(t, "0", Money(0).String())
+
+	amounts := make([]float64, 1000)
+	var floatTotal float64
+	for i := range amounts {
+		amounts[i] = 0.1
+		floatTotal += 0.1
+	}
+	assert.NotEqual(t, 100.0, floatTotal)
+	assert.Equal(t, "100", SumMoney(amounts...).String())
+	assert.Equal(t, 100.0, moneyFloat(SumMoney(amounts...)))
+
+	for _, f := range []float64{0.1, 47224, 1234.56, 0.015, 1e-7, 123456789.99} {
+		assert.Equal(t, f, moneyFloat(Money(f)))
+	}
+}
+
+func TestClaimAmounts(t *testing.T) {
+	t.Parallel()
+	claim := Claim{BilledAmount: 0.3, AllowedAmount: 0.2, Services: []Service{
+		{LineNumber: "1", BilledAmount: 0.1, AllowedAmount: 0.1, PaidAmount: 0.05},
+		{LineNumber: "2", BilledAmount: 0.2, AllowedAmount: 0.1, PaidAmount: 0.05},
+	}}
+	a := claim.Amounts()
+	assert.Equal(t, "0.3", a.BilledAmount.String())
+	assert.Equal(t, "0", a.PaidAmount.String())
+	assert.Len(t, a.Services, 2)
+	assert.Equal(t, "2", a.Services[1].LineNumber)
+
+	totals := a.ServiceTotals()
+	assert.True(t, totals.BilledAmount.Equal(a.BilledAmount))
+	assert.True(t, totals.AllowedAmount.Equal(a.AllowedAmount))
+	assert.Equal(t, "0.1", totals.PaidAmount.String())
+
+	assert.Equal(t, claim, claim.WithAmounts(a))
+	a.Services[0].AllowedAmount = Money(0.08)
+	updated := claim.WithAmounts(a)
+	assert.Equal(t, 0.08, updated.Services[0].AllowedAmount)
+	assert.Equal(t, 0.1, claim.Services[0].AllowedAmount)
+	assert.Nil(t, Claim{}.Amounts().Services)
+}
+
+func TestPricingAmounts(t *testing.T) {
+	t.Parallel()
+	pricing := Pricing{MedicareAmount: 100.3, AllowedAmount: 150.45, Services: []PricedService{
+		{LineNumber: "1", MedicareAmount: 100.1, AllowedAmount: 150.15, TechnicalComponentAmount: 60.05, ProfessionalComponentAmount: 40.05},
+		{LineNumber: "2", MedicareAmount: 0.2, AllowedAmount: 0.3},
+	}}
+	a := pricing.Amounts()
+	totals := a.ServiceTotals()
+	assert.True(t, totals.MedicareAmount.Equal(a.MedicareAmount))
+	assert.True(t, totals.AllowedAmount.Equal(a.AllowedAmount))
+	assert.Equal(t, "100.1", totals.TechnicalComponentAmount.Add(totals.ProfessionalComponentAmount).String())
+	assert.Equal(t, pricing, pricing.WithAmounts(a))
+}
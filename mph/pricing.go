@@ -1,20 +1,22 @@
 package mph
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
 	"github.com/mypricehealth/mphgo/set"
 )
 
 const (
-	editErrorTitle        = "claim edits failed"
-	fatalEditErrorTitle   = "fatal edit error"
-	editErrorDetail       = "see editDetail for more information"
-	PriceErrorTitle       = "pricing not available"
-	SyntheticPricerResult = "Processed via synthetic Medicare"
+	editErrorTitle          = "claim edits failed"
+	fatalEditErrorTitle     = "fatal edit error"
+	editErrorDetail         = "see editDetail for more information"
+	PriceErrorTitle         = "pricing not available"
+	ProviderNotMatchedTitle = "provider not matched"
+	SyntheticPricerResult   = "Processed via synthetic Medicare"
 )
 
 var (
@@ -34,28 +36,42 @@ var _ json.Unmarshaler = new(RuralIndicator)
 
 func (r *RuralIndicator) UnmarshalJSON(data []byte) error { // old code was sending the int value of the Rural indicator, so this handles both
 	var strData string
-	err := json.Unmarshal(data, &strData)
-	if err == nil && (strData == "R" || strData == "B" || strData == "") {
-		*r = RuralIndicator(strData)
-		return nil
+	if err := json.Unmarshal(data, &strData); err == nil {
+		if indicator, err := ruralIndicatorFromString(strData); err == nil {
+			*r = indicator
+			return nil
+		}
 	}
 
-	var intData int
-	err = json.Unmarshal(data, &intData)
-	if err == nil {
-		switch intData {
-		case 66:
-			*r = RuralIndicator("B")
-			return nil
-		case 82:
-			*r = RuralIndicator("R")
-			return nil
-		case 0:
-			*r = RuralIndicator("")
+	var intData int64
+	if err := json.Unmarshal(data, &intData); err == nil {
+		if indicator, err := ruralIndicatorFromInt(intData); err == nil {
+			*r = indicator
 			return nil
 		}
 	}
-	return errtrace.Errorf("invalid RuralIndicator value: %s", strData)
+	return errtrace.Errorf("invalid RuralIndicator value: %s", data)
+}
+
+func ruralIndicatorFromString(s string) (RuralIndicator, error) {
+	switch RuralIndicator(s) {
+	case RuralIndicatorRural, RuralIndicatorSuperRural, RuralIndicatorUrban:
+		return RuralIndicator(s), nil
+	}
+	return "", errtrace.Errorf("invalid RuralIndicator value: %s", s)
+}
+
+// ruralIndicatorFromInt converts the character code of a rural indicator which was sent by older versions of the API.
+func ruralIndicatorFromInt(i int64) (RuralIndicator, error) {
+	switch i {
+	case 'B':
+		return RuralIndicatorSuperRural, nil
+	case 'R':
+		return RuralIndicatorRural, nil
+	case 0:
+		return RuralIndicatorUrban, nil
+	}
+	return "", errtrace.Errorf("invalid RuralIndicator value: %d", i)
 }
 
 const (
@@ -101,33 +117,8 @@ const (
 )
 
 var (
-	ClaimRepricingCodes = map[ClaimRepricingCode]struct{}{
-		ClaimRepricingCodeMedicare:            {},
-		ClaimRepricingCodeContractPricing:     {},
-		ClaimRepricingCodeRBPPricing:          {},
-		ClaimRepricingCodeCoralRBPPricing:     {},
-		ClaimRepricingCodeSingleCaseAgreement: {},
-		ClaimRepricingCodeNeedsMoreInfo:       {},
-		ClaimRepricingCodeOutOfNetwork:        {},
-	}
-	LineRepricingCodes = map[LineRepricingCode]struct{}{
-		LineRepricingCodeMedicare:              {},
-		LineRepricingCodeMedicarePercent:       {},
-		LineRepricingCodeMedicareNoOutlier:     {},
-		LineRepricingCodeSyntheticMedicare:     {},
-		LineRepricingCodeBilledPercent:         {},
-		LineRepricingCodeFeeSchedule:           {},
-		LineRepricingCodePerDiem:               {},
-		LineRepricingCodeFlatRate:              {},
-		LineRepricingCodeCostPercent:           {},
-		LineRepricingCodeLimitedToBilled:       {},
-		LineRepricingCodeNotRepricedPerRequest: {},
-		LineRepricingCodeNotAllowedByMedicare:  {},
-		LineRepricingCodePackaged:              {},
-		LineRepricingCodeNeedsMoreInfo:         {},
-		LineRepricingCodeProcedureCodeProblem:  {},
-		LineRepricingCodeOutOfNetwork:          {},
-	}
+	ClaimRepricingCodes = claimRepricingCodes.set() // every valid claim repricing code, see ClaimRepricingCode.IsValid
+	LineRepricingCodes  = lineRepricingCodes.set()  // every valid line repricing code, see LineRepricingCode.IsValid
 )
 
 const (
@@ -162,29 +153,7 @@ const (
 )
 
 var (
-	MedicareSources = map[MedicareSource]struct{}{
-		MedicareSourceAmbulance:              {},
-		MedicareSourceAnesthesia:             {},
-		MedicareSourceASC:                    {},
-		MedicareSourceCriticalAccessHospital: {},
-		MedicareSourceDME:                    {},
-		MedicareSourceDrugs:                  {},
-		MedicareSourceEditError:              {},
-		MedicareSourceEstimateByCodeOnly:     {},
-		MedicareSourceEstimateByLocalityCode: {},
-		MedicareSourceEstimateByLocalityOnly: {},
-		MedicareSourceEstimateByNational:     {},
-		MedicareSourceEstimateByStateCode:    {},
-		MedicareSourceEstimateByStateOnly:    {},
-		MedicareSourceEstimateByUnknown:      {},
-		MedicareSourceInpatient:              {},
-		MedicareSourceLabs:                   {},
-		MedicareSourceMPFS:                   {},
-		MedicareSourceOutpatient:             {},
-		MedicareSourceManualPricing:          {},
-		MedicareSourceSNF:                    {},
-		MedicareSourceSynthetic:              {},
-	}
+	MedicareSources = medicareSources.set() // every valid Medicare source, see MedicareSource.IsValid
 )
 
 const (
@@ -279,7 +248,7 @@ type Pricing struct {
 	EditDetail            *ClaimEdits           `json:"editDetail,omitzero"            db:",inline"`                 // Errors which cause the claim to be denied, rejected, suspended, or returned to the provider
 	PricerResult          string                `json:"pricerResult,omitzero"          db:"pricer_result"`           // Pricer return details
 	PriceConfig           PriceConfig           `json:"priceConfig,omitzero"           db:",inline"`                 // The configuration used for pricing the claim
-	Services              []PricedService       `json:"services,omitzero,omitempty"    db:"services"`                // Pricing for each service line on the claim
+	Services              PricedServices        `json:"services,omitzero,omitempty"    db:"services"`                // Pricing for each service line on the claim
 	EditError             *ResponseError        `json:"editError,omitzero"             db:"edit_error"`              // An error that occurred during some step of the pricing process
 }
 
@@ -328,9 +297,12 @@ func (p Pricing) GetEditMessages() []string {
 }
 
 func (p Pricing) HasFatalError() bool {
-	return p.EditError != nil && p.EditError.Title == fatalEditErrorTitle
+	return errors.Is(p.EditError, ErrFatalEdit)
 }
 
+// PricedServices contains the pricing for each service line of a claim. It is stored in a database as a JSON array.
+type PricedServices []PricedService
+
 // PricedService contains the results of a pricing request for a single service line.
 type PricedService struct {
 	LineNumber                    string                  `json:"lineNumber,omitzero"                    db:"-"`                                  // Number of the service line item (copied from input)
@@ -441,22 +413,25 @@ func (p *ProviderDetail) IsEmpty() bool {
 	return p == nil || *p == empty
 }
 
+// EditReasons lists the reasons or descriptions of edit errors. It is stored in a database as a list, see SQLList.
+type EditReasons []string
+
 // ClaimEdits contains errors which cause the claim to be denied, rejected, suspended, or returned to the provider.
 type ClaimEdits struct {
-	HCP13DenyCode                    string   `json:"hcpDenyCode,omitzero"                      db:"hcp_deny_code"`                             // The deny code that will be placed into the HCP13 data element for EDI 837 claims
-	ClaimOverallDisposition          string   `json:"claimOverallDisposition,omitzero"          db:"claim_edit_overall_disposition"`            // Overall explanation of why the claim edit failed
-	ClaimRejectionDisposition        string   `json:"claimRejectionDisposition,omitzero"        db:"claim_edit_rejection_disposition"`          // Explanation of why the claim was rejected
-	ClaimDenialDisposition           string   `json:"claimDenialDisposition,omitzero"           db:"claim_edit_denial_disposition"`             // Explanation of why the claim was denied
-	ClaimReturnToProviderDisposition string   `json:"claimReturnToProviderDisposition,omitzero" db:"claim_edit_return_to_provider_disposition"` // Explanation of why the claim should be returned to provider
-	ClaimSuspensionDisposition       string   `json:"claimSuspensionDisposition,omitzero"       db:"claim_edit_suspension_disposition"`         // Explanation of why the claim was suspended
-	LineItemRejectionDisposition     string   `json:"lineItemRejectionDisposition,omitzero"     db:"line_item_edit_rejection_disposition"`      // Explanation of why the line item was rejected
-	LineItemDenialDisposition        string   `json:"lineItemDenialDisposition,omitzero"        db:"line_item_edit_denial_disposition"`         // Explanation of why the line item was denied
-	ClaimRejectionReasons            []string `json:"claimRejectionReasons,omitzero"            db:"claim_edit_rejection_reasons"`              // Detailed reason(s) describing why the claim was rejected
-	ClaimDenialReasons               []string `json:"claimDenialReasons,omitzero"               db:"claim_edit_denial_reasons"`                 // Detailed reason(s) describing why the claim was denied
-	ClaimReturnToProviderReasons     []string `json:"claimReturnToProviderReasons,omitzero"     db:"claim_edit_return_to_provider_reasons"`     // Detailed reason(s) describing why the claim should be returned to provider
-	ClaimSuspensionReasons           []string `json:"claimSuspensionReasons,omitzero"           db:"claim_edit_suspension_reasons"`             // Detailed reason(s) describing why the claim was suspended
-	LineItemRejectionReasons         []string `json:"lineItemRejectionReasons,omitzero"         db:"line_item_edit_rejection_reasons"`          // Detailed reason(s) describing why the line item was rejected
-	LineItemDenialReasons            []string `json:"lineItemDenialReasons,omitzero"            db:"line_item_edit_denial_reasons"`             // Detailed reason(s) describing why the line item was denied
+	HCP13DenyCode                    string      `json:"hcpDenyCode,omitzero"                      db:"hcp_deny_code"`                             // The deny code that will be placed into the HCP13 data element for EDI 837 claims
+	ClaimOverallDisposition          string      `json:"claimOverallDisposition,omitzero"          db:"claim_edit_overall_disposition"`            // Overall explanation of why the claim edit failed
+	ClaimRejectionDisposition        string      `json:"claimRejectionDisposition,omitzero"        db:"claim_edit_rejection_disposition"`          // Explanation of why the claim was rejected
+	ClaimDenialDisposition           string      `json:"claimDenialDisposition,omitzero"           db:"claim_edit_denial_disposition"`             // Explanation of why the claim was denied
+	ClaimReturnToProviderDisposition string      `json:"claimReturnToProviderDisposition,omitzero" db:"claim_edit_return_to_provider_disposition"` // Explanation of why the claim should be returned to provider
+	ClaimSuspensionDisposition       string      `json:"claimSuspensionDisposition,omitzero"       db:"claim_edit_suspension_disposition"`         // Explanation of why the claim was suspended
+	LineItemRejectionDisposition     string      `json:"lineItemRejectionDisposition,omitzero"     db:"line_item_edit_rejection_disposition"`      // Explanation of why the line item was rejected
+	LineItemDenialDisposition        string      `json:"lineItemDenialDisposition,omitzero"        db:"line_item_edit_denial_disposition"`         // Explanation of why the line item was denied
+	ClaimRejectionReasons            EditReasons `json:"claimRejectionReasons,omitzero"            db:"claim_edit_rejection_reasons"`              // Detailed reason(s) describing why the claim was rejected
+	ClaimDenialReasons               EditReasons `json:"claimDenialReasons,omitzero"               db:"claim_edit_denial_reasons"`                 // Detailed reason(s) describing why the claim was denied
+	ClaimReturnToProviderReasons     EditReasons `json:"claimReturnToProviderReasons,omitzero"     db:"claim_edit_return_to_provider_reasons"`     // Detailed reason(s) describing why the claim should be returned to provider
+	ClaimSuspensionReasons           EditReasons `json:"claimSuspensionReasons,omitzero"           db:"claim_edit_suspension_reasons"`             // Detailed reason(s) describing why the claim was suspended
+	LineItemRejectionReasons         EditReasons `json:"lineItemRejectionReasons,omitzero"         db:"line_item_edit_rejection_reasons"`          // Detailed reason(s) describing why the line item was rejected
+	LineItemDenialReasons            EditReasons `json:"lineItemDenialReasons,omitzero"            db:"line_item_edit_denial_reasons"`             // Detailed reason(s) describing why the line item was denied
 }
 
 func (e *ClaimEdits) IsEmpty() bool {
@@ -477,14 +452,14 @@ func (e *ClaimEdits) GetMessage() string {
 
 // LineEdits contains errors which cause the line item to be unable to be priced.
 type LineEdits struct {
-	ProcedureEdits []string `json:"procedureEdits,omitzero" db:"procedure_edits"` // Detailed description of each procedure code edit error (from outpatient editor)
-	Modifier1Edits []string `json:"modifier1Edits,omitzero" db:"modifier1_edits"` // Detailed description of each edit error for the first procedure code modifier (from outpatient editor)
-	Modifier2Edits []string `json:"modifier2Edits,omitzero" db:"modifier2_edits"` // Detailed description of each edit error for the second procedure code modifier (from outpatient editor)
-	Modifier3Edits []string `json:"modifier3Edits,omitzero" db:"modifier3_edits"` // Detailed description of each edit error for the third procedure code modifier (from outpatient editor)
-	Modifier4Edits []string `json:"modifier4Edits,omitzero" db:"modifier4_edits"` // Detailed description of each edit error for the fourth procedure code modifier (from outpatient editor)
-	Modifier5Edits []string `json:"modifier5Edits,omitzero" db:"modifier5_edits"` // Detailed description of each edit error for the fifth procedure code modifier (from outpatient editor)
-	DataEdits      []string `json:"dataEdits,omitzero"      db:"data_edits"`      // Detailed description of each data edit error (from outpatient editor)
-	RevenueEdits   []string `json:"revenueEdits,omitzero"   db:"revenue_edits"`   // Detailed description of each revenue code edit error (from outpatient editor)
+	ProcedureEdits EditReasons `json:"procedureEdits,omitzero" db:"procedure_edits"` // Detailed description of each procedure code edit error (from outpatient editor)
+	Modifier1Edits EditReasons `json:"modifier1Edits,omitzero" db:"modifier1_edits"` // Detailed description of each edit error for the first procedure code modifier (from outpatient editor)
+	Modifier2Edits EditReasons `json:"modifier2Edits,omitzero" db:"modifier2_edits"` // Detailed description of each edit error for the second procedure code modifier (from outpatient editor)
+	Modifier3Edits EditReasons `json:"modifier3Edits,omitzero" db:"modifier3_edits"` // Detailed description of each edit error for the third procedure code modifier (from outpatient editor)
+	Modifier4Edits EditReasons `json:"modifier4Edits,omitzero" db:"modifier4_edits"` // Detailed description of each edit error for the fourth procedure code modifier (from outpatient editor)
+	Modifier5Edits EditReasons `json:"modifier5Edits,omitzero" db:"modifier5_edits"` // Detailed description of each edit error for the fifth procedure code modifier (from outpatient editor)
+	DataEdits      EditReasons `json:"dataEdits,omitzero"      db:"data_edits"`      // Detailed description of each data edit error (from outpatient editor)
+	RevenueEdits   EditReasons `json:"revenueEdits,omitzero"   db:"revenue_edits"`   // Detailed description of each revenue code edit error (from outpatient editor)
 }
 
 func (e *LineEdits) IsEmpty() bool {
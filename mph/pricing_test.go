@@ -3,6 +3,7 @@ package mph
 import (
 	"testing"
 
+	"github.com/go-json-experiment/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,6 +29,18 @@ func TestRuralIndicatorUnmarshalJSON(t *testing.T) {
 	err = r.UnmarshalJSON([]byte(`0`))
 	require.NoError(t, err)
 	require.Equal(t, RuralIndicatorUrban, r)
+
+	err = r.UnmarshalJSON([]byte(`1`))
+	require.EqualError(t, err, "invalid RuralIndicator value: 1")
+}
+
+func TestPricingEmptyServicesJSON(t *testing.T) {
+	t.Parallel()
+	for _, services := range []PricedServices{nil, {}} {
+		data, err := json.Marshal(Pricing{ClaimID: "1", Services: services})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"claimID":"1"}`, string(data))
+	}
 }
 
 func TestGetClaimRepricingNote(t *testing.T) {
@@ -123,3 +136,21 @@ func TestLineEditsGetMessage(t *testing.T) {
 	}
 	assert.Equal(t, "test1|test2", l.GetMessage())
 }
+
+func FuzzRuralIndicatorJSON(f *testing.F) {
+	for _, seed := range []string{`"R"`, `"B"`, `""`, `0`, `66`, `82`, `"A"`, `1`, `null`, `82.0`, `true`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r RuralIndicator
+		if err := r.UnmarshalJSON(data); err != nil {
+			assert.Contains(t, err.Error(), "invalid RuralIndicator value: ")
+			if len(data) > 0 {
+				assert.NotEqual(t, "invalid RuralIndicator value: ", err.Error())
+			}
+			return
+		}
+		assert.Contains(t, []RuralIndicator{RuralIndicatorUrban, RuralIndicatorRural, RuralIndicatorSuperRural}, r)
+		checkCodecs[RuralIndicator](t, data)
+	})
+}
@@ -0,0 +1,85 @@
+package mph
+
+import (
+	"net/http"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem documents.
+const ProblemContentType = "application/problem+json"
+
+// responseErrorJSON has the same fields as ResponseError without its JSON methods.
+type responseErrorJSON ResponseError
+
+// MarshalJSON encodes the problem document including any extension members.
+func (r ResponseError) MarshalJSON() ([]byte, error) {
+	return errtrace.Wrap2(json.Marshal(responseErrorJSON(r)))
+}
+
+// UnmarshalJSON decodes a problem document with the default options.
+func (r *ResponseError) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, r))
+}
+
+// UnmarshalJSONFrom decodes a problem document using the options of dec, keeping members which are not defined by
+// ResponseError in Extensions. Extension members are compacted with minimal string escaping so that they are the same
+// whichever JSON package encoded them. Extension members are allowed by RFC 7807, so they are kept even when
+// decoding strictly.
+func (r *ResponseError) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	var rj responseErrorJSON
+	if err := json.UnmarshalDecode(dec, &rj, json.RejectUnknownMembers(false)); err != nil {
+		return errtrace.Wrap(err)
+	}
+	for name, value := range rj.Extensions {
+		if err := value.Format(); err != nil {
+			return errtrace.Wrap(err)
+		}
+		rj.Extensions[name] = value
+	}
+	*r = ResponseError(rj)
+	return nil
+}
+
+// WriteProblem writes the problem document to w with the problem+json content type. The status of the problem is
+// used as the HTTP status code, defaulting to 500 when it is not set.
+func WriteProblem(w http.ResponseWriter, problem *ResponseError) error {
+	status := problem.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+		p := *problem
+		p.Status = status
+		problem = &p
+	}
+	data, err := json.Marshal(problem)
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return errtrace.Wrap(err)
+}
+
+// problemDocument returns the problem when data is a bare problem document rather than a response envelope
+// containing an error. It returns nil if data is not a problem document. It is called before the envelope is decoded
+// since the members of a problem document are unknown to the envelope when decoding strictly.
+func problemDocument(data []byte, opts json.Options) (*ResponseError, error) {
+	var probe struct {
+		Type  string `json:"type"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(data, &probe, jsontext.AllowDuplicateNames(true)); err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	if probe.Type == "" && probe.Title == "" {
+		return nil, nil
+	}
+	var problem ResponseError
+	if err := json.Unmarshal(data, &problem, opts); err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	return &problem, nil
+}
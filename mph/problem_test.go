@@ -0,0 +1,106 @@
+package mph
+
+import (
+	stdjson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProblem = `{
+	"type": "https://api.myprice.health/problems/validation",
+	"title": "invalid claim",
+	"status": 400,
+	"detail": "the claim has 2 errors",
+	"instance": "/v1/medicare/price/claim/123",
+	"errors": [
+		{"pointer": "/services/0/procedureCode", "detail": "unknown procedure code"},
+		{"pointer": "/dateFrom", "detail": "date is required"}
+	],
+	"traceID": "abc123",
+	"retry": {"after": 5}
+}`
+
+func TestResponseErrorJSON(t *testing.T) {
+	t.Parallel()
+	var problem ResponseError
+	require.NoError(t, json.Unmarshal([]byte(testProblem), &problem))
+	assert.Equal(t, "https://api.myprice.health/problems/validation", problem.Type)
+	assert.Equal(t, 400, problem.Status)
+	assert.Equal(t, "/v1/medicare/price/claim/123", problem.Instance)
+	assert.Equal(t, []ValidationError{
+		{Pointer: "/services/0/procedureCode", Detail: "unknown procedure code"},
+		{Pointer: "/dateFrom", Detail: "date is required"},
+	}, problem.Errors)
+	require.Len(t, problem.Extensions, 2)
+	assert.Equal(t, `"abc123"`, string(problem.Extensions["traceID"]))
+
+	// extension members are preserved by both JSON packages
+	data, err := json.Marshal(problem)
+	require.NoError(t, err)
+	assert.JSONEq(t, testProblem, string(data))
+	data, err = stdjson.Marshal(Pricing{EditError: &problem})
+	require.NoError(t, err)
+	var pricing Pricing
+	require.NoError(t, stdjson.Unmarshal(data, &pricing))
+	data, err = json.Marshal(pricing.EditError)
+	require.NoError(t, err)
+	assert.JSONEq(t, testProblem, string(data))
+
+	data, err = json.Marshal(ResponseError{Title: "foo", Detail: "bar"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title":"foo","detail":"bar"}`, string(data))
+}
+
+func TestResponseProblemDocument(t *testing.T) {
+	t.Parallel()
+	var response Response[Pricing]
+	require.NoError(t, json.Unmarshal([]byte(testProblem), &response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Len(t, response.Error.Errors, 2)
+	var responseErr *ResponseError
+	require.ErrorAs(t, response.GetError(), &responseErr)
+	assert.Equal(t, "/dateFrom", responseErr.Errors[1].Pointer)
+
+	var responses ErrorAndResultResponses[Pricing]
+	require.NoError(t, json.Unmarshal([]byte(testProblem), &responses))
+	require.NotNil(t, responses.Error)
+	assert.Equal(t, http.StatusBadRequest, responses.StatusCode)
+	assert.Equal(t, `"abc123"`, string(responses.Error.Extensions["traceID"]))
+
+	// an envelope with an error is unchanged
+	require.NoError(t, json.Unmarshal([]byte(`{"error":{"title":"foo","detail":"bar"},"status":422}`), &response))
+	assert.Equal(t, &ResponseError{Title: "foo", Detail: "bar"}, response.Error)
+	assert.Equal(t, 422, response.StatusCode)
+
+	// so is the legacy message and code shape
+	response = Response[Pricing]{}
+	require.NoError(t, json.Unmarshal([]byte(`{"message":"Unauthorized","code":401}`), &response))
+	assert.Equal(t, "Unauthorized", response.Error.Title)
+	assert.ErrorIs(t, response.GetError(), ErrAuthentication)
+
+	response = Response[Pricing]{}
+	require.NoError(t, json.Unmarshal([]byte(`{"result":{"medicareAmount":100},"status":200}`), &response))
+	assert.Nil(t, response.Error)
+}
+
+func TestWriteProblem(t *testing.T) {
+	t.Parallel()
+	w := httptest.NewRecorder()
+	require.NoError(t, WriteProblem(w, &ResponseError{Title: "invalid claim", Status: http.StatusBadRequest, Errors: []ValidationError{{Pointer: "/npi", Detail: "NPI is required"}}}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"invalid claim","status":400,"errors":[{"pointer":"/npi","detail":"NPI is required"}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	problem := &ResponseError{Title: "pricing failed"}
+	require.NoError(t, WriteProblem(w, problem))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"title":"pricing failed","status":500}`, w.Body.String())
+	assert.Zero(t, problem.Status, "the problem should not be modified")
+}
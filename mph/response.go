@@ -3,6 +3,7 @@ package mph
 import (
 	"braces.dev/errtrace"
 	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
 )
 
 // Response contains the standardized API response data used by all My Price Health API's. It is based off of the generalized error handling recommendation found
@@ -42,10 +43,7 @@ func (r Response[Result]) Unwrap() (Result, *Error) {
 }
 
 func (r Response[Result]) GetError() *Error {
-	if r.Error == nil {
-		return nil
-	}
-	return NewError(r.Error.Title, errtrace.Errorf("%s", r.Error.Detail), r.StatusCode)
+	return r.Error.toError(r.StatusCode)
 }
 
 type responseJSON[Result any] struct {
@@ -57,9 +55,28 @@ type responseJSON[Result any] struct {
 	StatusCode  int            `json:"status"`
 }
 
+// UnmarshalJSON decodes the response with the default options.
 func (r *Response[Result]) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, r))
+}
+
+// UnmarshalJSONFrom decodes the response using the options of dec. A bare problem document is decoded as the error
+// of the response.
+func (r *Response[Result]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	data, err := dec.ReadValue()
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	problem, err := problemDocument(data, dec.Options())
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	if problem != nil {
+		*r = Response[Result]{Error: problem, StatusCode: problem.Status}
+		return nil
+	}
 	var rj responseJSON[Result]
-	if err := json.Unmarshal(data, &rj); err != nil {
+	if err := json.Unmarshal(data, &rj, dec.Options()); err != nil {
 		return errtrace.Wrap(err)
 	}
 	r.Error = rj.Error
@@ -68,7 +85,10 @@ func (r *Response[Result]) UnmarshalJSON(data []byte) error {
 	r.ClaimStatus = rj.ClaimStatus
 	if rj.Code != 0 {
 		r.StatusCode = rj.Code
-		r.Error = &ResponseError{Title: rj.Message}
+		r.Error = &ResponseError{Title: rj.Message, Status: rj.Code}
+	}
+	if r.Error != nil && r.StatusCode == 0 {
+		r.StatusCode = r.Error.Status
 	}
 	return nil
 }
@@ -95,18 +115,49 @@ func (r *Response[Result]) UnmarshalJSON(data []byte) error {
 }
 */
 type ErrorAndResultResponses[Result any] struct {
-	Error        *ResponseError           `json:"error,omitempty"`   // supplied when entire response is an error
-	Results      []ErrorAndResult[Result] `json:"results,omitempty"` // A slice of results that will either be a successful result or an error.
-	SuccessCount int                      `json:"successCount"`      // count of successful results when WriteResults is called
-	ErrorCount   int                      `json:"errorCount"`        // count of errored results when WriteResults is called
-	StatusCode   int                      `json:"status"`            // supplied on success and error
+	Error        *ResponseError           `json:"error,omitzero"`   // supplied when entire response is an error
+	Results      []ErrorAndResult[Result] `json:"results,omitzero"` // A slice of results that will either be a successful result or an error.
+	SuccessCount int                      `json:"successCount"`     // count of successful results when WriteResults is called
+	ErrorCount   int                      `json:"errorCount"`       // count of errored results when WriteResults is called
+	StatusCode   int                      `json:"status"`           // supplied on success and error
 }
 
-func (r ErrorAndResultResponses[Result]) GetError() *Error {
-	if r.Error == nil {
+// errorAndResultResponsesJSON has the same fields as ErrorAndResultResponses without its JSON methods.
+type errorAndResultResponsesJSON[Result any] ErrorAndResultResponses[Result]
+
+// UnmarshalJSON decodes the response with the default options.
+func (r *ErrorAndResultResponses[Result]) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, r))
+}
+
+// UnmarshalJSONFrom decodes the response using the options of dec. A bare problem document is decoded as the error
+// of the response.
+func (r *ErrorAndResultResponses[Result]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	data, err := dec.ReadValue()
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	problem, err := problemDocument(data, dec.Options())
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	if problem != nil {
+		*r = ErrorAndResultResponses[Result]{Error: problem, StatusCode: problem.Status}
 		return nil
 	}
-	return NewError(r.Error.Title, errtrace.Errorf("%s", r.Error.Detail), r.StatusCode)
+	var rj errorAndResultResponsesJSON[Result]
+	if err := json.Unmarshal(data, &rj, dec.Options()); err != nil {
+		return errtrace.Wrap(err)
+	}
+	*r = ErrorAndResultResponses[Result](rj)
+	if r.Error != nil && r.StatusCode == 0 {
+		r.StatusCode = r.Error.Status
+	}
+	return nil
+}
+
+func (r ErrorAndResultResponses[Result]) GetError() *Error {
+	return r.Error.toError(r.StatusCode)
 }
 
 func (r ErrorAndResultResponses[Result]) Unwrap() ([]ErrorAndResult[Result], *Error) {
@@ -125,7 +176,7 @@ type ErrorAndResult[Result any] struct {
 
 // tmpErrorAndResult is being used until JSONv2 is the default. Until then, we're calling it just for this function
 type tmpErrorAndResult[Result any] struct {
-	Error       *ResponseError `json:"error,omitempty"`
+	Error       *ResponseError `json:"error,omitzero"`
 	Result      Result         `json:",inline"`
 	ClaimStatus ClaimStatus    `json:"claimStatus,omitzero"`
 }
@@ -134,6 +185,14 @@ func (e ErrorAndResult[Result]) Unwrap() (Result, *ResponseError) {
 	return e.Result, e.Error
 }
 
+// Err returns the error as an error interface which is nil when there is no error.
+func (e ErrorAndResult[Result]) Err() error {
+	if e.Error == nil {
+		return nil
+	}
+	return e.Error
+}
+
 func NewErrorAndResult[Result any](result Result, err *ResponseError, status ClaimStatus) ErrorAndResult[Result] {
 	return ErrorAndResult[Result]{
 		Error:       err,
@@ -146,9 +205,15 @@ func (e ErrorAndResult[Result]) MarshalJSON() ([]byte, error) {
 	return errtrace.Wrap2(json.Marshal(tmpErrorAndResult[Result](e)))
 }
 
+// UnmarshalJSON decodes the error and result with the default options.
 func (e *ErrorAndResult[Result]) UnmarshalJSON(data []byte) error {
+	return errtrace.Wrap(Unmarshal(data, e))
+}
+
+// UnmarshalJSONFrom decodes the error and result using the options of dec.
+func (e *ErrorAndResult[Result]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
 	var tmp tmpErrorAndResult[Result]
-	if err := json.Unmarshal(data, &tmp); err != nil {
+	if err := json.UnmarshalDecode(dec, &tmp); err != nil {
 		return errtrace.Wrap(err)
 	}
 	*e = ErrorAndResult[Result](tmp)
@@ -140,9 +140,42 @@ func TestErrorAndResultUnwrap(t *testing.T) {
 	t.Parallel()
 
 	res := testStruct{StrVal: "baz", IntVal: 42}
-	expected := &ResponseError{"foo", "bar"}
+	expected := &ResponseError{Title: "foo", Detail: "bar"}
 	v := ErrorAndResult[testStruct]{Result: res, Error: expected}
 	result, err := v.Unwrap()
 	assert.Equal(t, res, result)
 	assert.Equal(t, expected, err)
 }
+
+func FuzzResponseJSON(f *testing.F) {
+	for _, seed := range []string{
+		`{"result": {"medicareAmount": 100}, "status": 200}`,
+		`{"error": {"title": "title", "detail": "detail"}, "status": 500}`,
+		`{"message": "Unauthorized", "code": 401}`,
+		`{"title": "invalid claim", "status": 400, "traceID": "abc"}`,
+		`{"result": {"services": [{"lineNumber": "1"}], "providerDetail": {"ruralIndicator": 82}}, "claimStatus": {"step": "Pending"}, "status": 200}`,
+		`null`,
+		`{}`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkCodecs[Response[Pricing]](t, data)
+	})
+}
+
+func FuzzErrorAndResultJSON(f *testing.F) {
+	for _, seed := range []string{
+		`{"claimID": "1", "medicareAmount": 100}`,
+		`{"error": {"title": "title", "detail": "detail"}}`,
+		`{"error": {"title": "title"}, "claimID": "1", "claimStatus": {"step": "Priced"}}`,
+		`{"results": [{"claimID": "1"}, {"error": {"title": "lorem"}}], "successCount": 1, "errorCount": 1, "status": 200}`,
+		`null`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkCodecs[ErrorAndResult[Pricing]](t, data)
+		checkCodecs[ErrorAndResultResponses[Pricing]](t, data)
+	})
+}
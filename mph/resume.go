@@ -0,0 +1,216 @@
+package mph
+
+import (
+	"context"
+	"errors"
+
+	"braces.dev/errtrace"
+)
+
+// StopReason classifies why processing of a partially priced claim stopped.
+type StopReason string
+
+const (
+	StopReasonNone                StopReason = ""                    // the claim was priced
+	StopReasonProviderMatching    StopReason = "providerMatching"    // the provider could not be matched
+	StopReasonEdits               StopReason = "edits"               // the claim failed edits
+	StopReasonMedicareCalculation StopReason = "medicareCalculation" // the Medicare amount could not be calculated
+	StopReasonOther               StopReason = "other"               // the claim failed for any other reason
+)
+
+// ClassifyStop uses the claim status of a partial result to determine why processing stopped. The claim
+// status is the last step that completed, so a claim which reached Provider Matched stopped during edits.
+// When there is no claim status, the error is used instead.
+func ClassifyStop(result ErrorAndResult[Pricing]) StopReason {
+	switch result.ClaimStatus {
+	case StatusReceived, StatusInputValidated, StatusPendingProviderMatching:
+		return StopReasonProviderMatching
+	case StatusProviderMatched, StatusPendingClaimEditReview:
+		return StopReasonEdits
+	case StatusEditComplete, StatusPendingMedicareCalculation, StatusPendingMedicareReview:
+		return StopReasonMedicareCalculation
+	}
+	switch {
+	case result.Error == nil:
+		return StopReasonNone
+	case errors.Is(result.Error, ErrProviderNotMatched):
+		return StopReasonProviderMatching
+	case errors.Is(result.Error, ErrEditFailure):
+		return StopReasonEdits
+	}
+	return StopReasonOther
+}
+
+// Escalation adjusts the configuration used to re-submit a claim.
+type Escalation struct {
+	Name  string             // Name of the escalation recorded with each step
+	Apply func(*PriceConfig) // Apply modifies the configuration
+}
+
+var (
+	EscalateContinueOnProviderMatchFail = Escalation{Name: "continue on provider match fail", Apply: func(c *PriceConfig) { c.ContinueOnProviderMatchFail = true }}
+	EscalateContinueOnEditFail          = Escalation{Name: "continue on edit fail", Apply: func(c *PriceConfig) { c.ContinueOnEditFail = true }}
+	EscalateCommercialSynthetic         = Escalation{Name: "use commercial synthetic for not allowed", Apply: func(c *PriceConfig) { c.UseCommercialSyntheticForNotAllowed = true }}
+)
+
+// EscalateOverrideThreshold allows the pricer to override edits and other overridable errors up to threshold.
+func EscalateOverrideThreshold(threshold float64) Escalation {
+	return Escalation{Name: "override threshold", Apply: func(c *PriceConfig) { c.OverrideThreshold = threshold }}
+}
+
+// EscalationPolicy lists the escalations to try, in order, for each reason a claim stopped. Escalations are
+// cumulative: the second attempt for a reason includes the adjustments of the first.
+type EscalationPolicy map[StopReason][]Escalation
+
+// DefaultEscalationPolicy continues with an average provider when the provider can't be matched, ignores
+// edit failures, and uses a synthetic price for services Medicare does not allow.
+func DefaultEscalationPolicy() EscalationPolicy {
+	return EscalationPolicy{
+		StopReasonProviderMatching:    {EscalateContinueOnProviderMatchFail},
+		StopReasonEdits:               {EscalateContinueOnEditFail},
+		StopReasonMedicareCalculation: {EscalateCommercialSynthetic},
+	}
+}
+
+// ResumeStep records a single re-submission of a claim.
+type ResumeStep struct {
+	Attempt     int            `json:"attempt"`              // Attempt number starting at 1
+	Reason      StopReason     `json:"reason"`               // Why the claim stopped before this attempt
+	Escalation  string         `json:"escalation"`           // Name of the escalation applied
+	Config      PriceConfig    `json:"config"`               // Configuration used for the attempt
+	ClaimStatus ClaimStatus    `json:"claimStatus,omitzero"` // The step the claim processing reached
+	Error       *ResponseError `json:"error,omitzero"`       // Error returned for the claim
+}
+
+// ResumeResult contains the final result for a claim after resuming along with each step taken.
+type ResumeResult struct {
+	ClaimID string                  `json:"claimID"`
+	Result  ErrorAndResult[Pricing] `json:"result"`
+	Reason  StopReason              `json:"reason,omitzero"` // Why the claim is still incomplete (empty if priced)
+	Steps   []ResumeStep            `json:"steps,omitzero"`
+}
+
+// Resumer re-submits partially priced claims with an adjusted configuration according to an escalation policy.
+type Resumer struct {
+	Pricer      Pricer           // Pricer used to re-submit claims
+	Policy      EscalationPolicy // Escalations to try for each reason. DefaultEscalationPolicy is used when nil
+	History     HistoryStore     // When set, each attempt is recorded in the history of the claim
+	MaxAttempts int              // Maximum number of re-submissions per claim. Unlimited when zero
+}
+
+type resumeState struct {
+	config   PriceConfig
+	attempts int
+	levels   map[StopReason]int // number of escalations applied for each reason
+	done     bool
+}
+
+// Resume inspects pricing results returned with AllowPartialResults and re-submits the claims which stopped
+// early. Results must be in the same order as the claims. Claims are re-submitted in batches grouped by their
+// adjusted configuration until they are priced or the policy has no more escalations for the reason they stopped.
+func (r Resumer) Resume(ctx context.Context, config PriceConfig, claims []Claim, results []ErrorAndResult[Pricing]) ([]ResumeResult, error) {
+	if len(claims) != len(results) {
+		return nil, errtrace.Errorf("got %d claims but %d pricing results", len(claims), len(results))
+	}
+	policy := r.Policy
+	if policy == nil {
+		policy = DefaultEscalationPolicy()
+	}
+	config.AllowPartialResults = true
+
+	resumed := make([]ResumeResult, len(claims))
+	states := make([]resumeState, len(claims))
+	histories := map[string]*ClaimHistory{} // histories loaded during this run, keyed by claim ID
+	for i, claim := range claims {
+		resumed[i] = ResumeResult{ClaimID: claim.ClaimID, Result: results[i], Reason: ClassifyStop(results[i])}
+		states[i] = resumeState{config: config, levels: map[StopReason]int{}}
+	}
+
+	for {
+		// escalate each incomplete claim and group them by the resulting configuration
+		var configs []PriceConfig
+		batches := map[PriceConfig][]int{}
+		for i := range claims {
+			state := &states[i]
+			reason := resumed[i].Reason
+			if state.done || reason == StopReasonNone || r.MaxAttempts > 0 && state.attempts >= r.MaxAttempts {
+				continue
+			}
+			escalation, ok := state.escalate(policy[reason], reason)
+			if !ok {
+				state.done = true
+				continue
+			}
+			state.attempts++
+			resumed[i].Steps = append(resumed[i].Steps, ResumeStep{Attempt: state.attempts, Reason: reason, Escalation: escalation.Name, Config: state.config})
+			if _, ok := batches[state.config]; !ok {
+				configs = append(configs, state.config)
+			}
+			batches[state.config] = append(batches[state.config], i)
+		}
+		if len(configs) == 0 {
+			return resumed, nil
+		}
+
+		for _, batchConfig := range configs {
+			indexes := batches[batchConfig]
+			inputs := make([]Claim, len(indexes))
+			for j, i := range indexes {
+				inputs[j] = claims[i]
+			}
+			responses := r.Pricer.PriceBatch(ctx, batchConfig, inputs...)
+			batchResults, err := responses.Unwrap()
+			if err != nil {
+				return resumed, errtrace.Wrap(err)
+			}
+			if len(batchResults) != len(inputs) {
+				return resumed, errtrace.Errorf("got %d results for %d claims", len(batchResults), len(inputs))
+			}
+			for j, i := range indexes {
+				result := batchResults[j]
+				step := &resumed[i].Steps[len(resumed[i].Steps)-1]
+				step.ClaimStatus = result.ClaimStatus
+				step.Error = result.Error
+				resumed[i].Result = result
+				resumed[i].Reason = ClassifyStop(result)
+				if err := r.record(ctx, histories, batchConfig, claims[i], result); err != nil {
+					return resumed, errtrace.Wrap(err)
+				}
+			}
+		}
+	}
+}
+
+// escalate applies the next escalation for reason which changes the configuration. Escalations which would not
+// change the configuration, such as one already applied for another reason, are skipped since re-submitting the
+// claim would give the same result. It returns false when there are no more escalations for reason.
+func (s *resumeState) escalate(escalations []Escalation, reason StopReason) (Escalation, bool) {
+	for s.levels[reason] < len(escalations) {
+		escalation := escalations[s.levels[reason]]
+		s.levels[reason]++
+		config := s.config
+		escalation.Apply(&config)
+		if config != s.config {
+			s.config = config
+			return escalation, true
+		}
+	}
+	return Escalation{}, false
+}
+
+// record records a pricing attempt in the history of the claim. Each history is loaded from the store the first
+// time it is needed and kept in histories for the rest of the run.
+func (r Resumer) record(ctx context.Context, histories map[string]*ClaimHistory, config PriceConfig, claim Claim, result ErrorAndResult[Pricing]) error {
+	if r.History == nil || claim.ClaimID == "" {
+		return nil
+	}
+	history, ok := histories[claim.ClaimID]
+	if !ok {
+		var err error
+		if history, err = LoadClaimHistory(ctx, r.History, claim.ClaimID); err != nil {
+			return errtrace.Wrap(err)
+		}
+		histories[claim.ClaimID] = history
+	}
+	return errtrace.Wrap(history.RecordPricing(ctx, config, claim, result, "resume"))
+}
@@ -0,0 +1,176 @@
+package mph
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialPricer prices claims as far as the configuration allows. Claim 1 needs ContinueOnProviderMatchFail
+// and then ContinueOnEditFail. Claim 2 can't be priced no matter the configuration.
+type partialPricer struct {
+	batches []PriceConfig
+}
+
+func (p *partialPricer) Price(ctx context.Context, config PriceConfig, input Claim) Response[Pricing] {
+	panic("not implemented")
+}
+
+func (p *partialPricer) PriceBatch(ctx context.Context, config PriceConfig, inputs ...Claim) ErrorAndResultResponses[Pricing] {
+	p.batches = append(p.batches, config)
+	var results []ErrorAndResult[Pricing]
+	for _, input := range inputs {
+		result := ErrorAndResult[Pricing]{Result: Pricing{ClaimID: input.ClaimID}}
+		switch {
+		case input.ClaimID == "2" || !config.ContinueOnProviderMatchFail:
+			result.ClaimStatus = StatusInputValidated
+			result.Error = &ResponseError{Title: ProviderNotMatchedTitle}
+		case !config.ContinueOnEditFail:
+			result.ClaimStatus = StatusProviderMatched
+			result.Error = &ResponseError{Title: "edit failure"}
+		default:
+			result.Result.MedicareAmount = 100
+		}
+		results = append(results, result)
+	}
+	return ErrorAndResultResponses[Pricing]{Results: results, StatusCode: 200}
+}
+
+func (p *partialPricer) EstimateClaims(ctx context.Context, inputs ...Claim) ErrorAndResultResponses[Pricing] {
+	panic("not implemented")
+}
+
+func (p *partialPricer) EstimateRateSheet(ctx context.Context, inputs ...RateSheet) ErrorAndResultResponses[Pricing] {
+	panic("not implemented")
+}
+
+func TestClassifyStop(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, StopReasonNone, ClassifyStop(ErrorAndResult[Pricing]{}))
+	assert.Equal(t, StopReasonNone, ClassifyStop(ErrorAndResult[Pricing]{ClaimStatus: StatusMedicarePriced}))
+	assert.Equal(t, StopReasonOther, ClassifyStop(ErrorAndResult[Pricing]{Error: &ResponseError{Title: "bad"}}))
+	assert.Equal(t, StopReasonProviderMatching, ClassifyStop(ErrorAndResult[Pricing]{ClaimStatus: StatusPendingProviderMatching}))
+	assert.Equal(t, StopReasonEdits, ClassifyStop(ErrorAndResult[Pricing]{ClaimStatus: StatusProviderMatched}))
+	assert.Equal(t, StopReasonMedicareCalculation, ClassifyStop(ErrorAndResult[Pricing]{ClaimStatus: StatusEditComplete}))
+	assert.Equal(t, StopReasonEdits, ClassifyStop(ErrorAndResult[Pricing]{Error: ErrorEditSeeDetail}))
+}
+
+func TestResume(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pricer := &partialPricer{}
+	store := NewFileHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	resumer := Resumer{Pricer: pricer, History: store}
+
+	claims := []Claim{{ClaimID: "1"}, {ClaimID: "2"}, {ClaimID: "3"}}
+	config := PriceConfig{IncludeEdits: true}
+	results := pricer.PriceBatch(ctx, config, claims...).Results
+	results[2] = ErrorAndResult[Pricing]{Result: Pricing{ClaimID: "3", MedicareAmount: 50}}
+	pricer.batches = nil
+
+	resumed, err := resumer.Resume(ctx, config, claims, results)
+	require.NoError(t, err)
+	require.Len(t, resumed, 3)
+
+	// claim 1 is escalated twice and priced
+	assert.Equal(t, StopReasonNone, resumed[0].Reason)
+	assert.Equal(t, 100.0, resumed[0].Result.Result.MedicareAmount)
+	require.Len(t, resumed[0].Steps, 2)
+	assert.Equal(t, StopReasonProviderMatching, resumed[0].Steps[0].Reason)
+	assert.Equal(t, EscalateContinueOnProviderMatchFail.Name, resumed[0].Steps[0].Escalation)
+	assert.Equal(t, StatusProviderMatched, resumed[0].Steps[0].ClaimStatus)
+	assert.Equal(t, StopReasonEdits, resumed[0].Steps[1].Reason)
+	assert.Equal(t, PriceConfig{IncludeEdits: true, AllowPartialResults: true, ContinueOnProviderMatchFail: true, ContinueOnEditFail: true}, resumed[0].Steps[1].Config)
+	assert.Nil(t, resumed[0].Steps[1].Error)
+
+	// claim 2 runs out of escalations
+	assert.Equal(t, StopReasonProviderMatching, resumed[1].Reason)
+	require.Len(t, resumed[1].Steps, 1)
+	assert.Equal(t, "provider not matched", resumed[1].Steps[0].Error.Title)
+
+	// claim 3 was already priced
+	assert.Equal(t, StopReasonNone, resumed[2].Reason)
+	assert.Empty(t, resumed[2].Steps)
+
+	// claims 1 and 2 share a batch for the first escalation
+	assert.Len(t, pricer.batches, 2)
+
+	history, err := LoadClaimHistory(ctx, store, "1")
+	require.NoError(t, err)
+	require.Len(t, history.Events, 2)
+	assert.Equal(t, "resume", history.Events[0].Actor)
+	assert.True(t, history.Events[1].Pricing.Config.ContinueOnEditFail)
+}
+
+func TestResumeMaxAttempts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pricer := &partialPricer{}
+	claims := []Claim{{ClaimID: "1"}}
+	results := pricer.PriceBatch(ctx, PriceConfig{}, claims...).Results
+
+	resumed, err := Resumer{Pricer: pricer, MaxAttempts: 1}.Resume(ctx, PriceConfig{}, claims, results)
+	require.NoError(t, err)
+	assert.Equal(t, StopReasonEdits, resumed[0].Reason)
+	assert.Len(t, resumed[0].Steps, 1)
+
+	_, err = Resumer{Pricer: pricer}.Resume(ctx, PriceConfig{}, claims, nil)
+	assert.Error(t, err)
+}
+
+// countingStore counts how many times the history of a claim is loaded.
+type countingStore struct {
+	HistoryStore
+	loads int
+}
+
+func (s *countingStore) Load(ctx context.Context, claimID string) ([]HistoryEvent, error) {
+	s.loads++
+	return s.HistoryStore.Load(ctx, claimID)
+}
+
+func TestResumeLoadsHistoryOnce(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pricer := &partialPricer{}
+	store := &countingStore{HistoryStore: NewFileHistoryStore(filepath.Join(t.TempDir(), "history.jsonl"))}
+	claims := []Claim{{ClaimID: "1"}}
+	results := pricer.PriceBatch(ctx, PriceConfig{}, claims...).Results
+
+	resumed, err := Resumer{Pricer: pricer, History: store}.Resume(ctx, PriceConfig{}, claims, results)
+	require.NoError(t, err)
+	assert.Len(t, resumed[0].Steps, 2)
+	assert.Equal(t, 1, store.loads)
+
+	history, err := LoadClaimHistory(ctx, store, "1")
+	require.NoError(t, err)
+	assert.Len(t, history.Events, 2)
+}
+
+func TestResumeSkipsUnchangedConfig(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pricer := &partialPricer{}
+	claims := []Claim{{ClaimID: "1"}}
+	config := PriceConfig{ContinueOnProviderMatchFail: true}
+	results := pricer.PriceBatch(ctx, PriceConfig{}, claims...).Results
+	pricer.batches = nil
+
+	// the first escalation is already part of the configuration so only the second is tried
+	policy := EscalationPolicy{StopReasonProviderMatching: {EscalateContinueOnProviderMatchFail, EscalateCommercialSynthetic}}
+	resumed, err := Resumer{Pricer: pricer, Policy: policy}.Resume(ctx, config, claims, results)
+	require.NoError(t, err)
+	require.Len(t, resumed[0].Steps, 1)
+	assert.Equal(t, EscalateCommercialSynthetic.Name, resumed[0].Steps[0].Escalation)
+	assert.Len(t, pricer.batches, 1)
+
+	// no escalation changes the configuration so the claim isn't re-submitted
+	policy = EscalationPolicy{StopReasonProviderMatching: {EscalateContinueOnProviderMatchFail}}
+	resumed, err = Resumer{Pricer: pricer, Policy: policy}.Resume(ctx, config, claims, results)
+	require.NoError(t, err)
+	assert.Empty(t, resumed[0].Steps)
+	assert.Len(t, pricer.batches, 1)
+}
@@ -1,9 +1,12 @@
 package mph
 
 import (
+	stdjson "encoding/json"
 	"testing"
 
+	"github.com/go-json-experiment/json"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetEditMessages(t *testing.T) {
@@ -36,3 +39,66 @@ func unmarshalRuralIndicator(t *testing.T, data []byte, expected RuralIndicator,
 	assert.Equal(t, isError, err != nil, "Want error: %v, Got: %s", isError, err)
 	assert.Equal(t, expected, r)
 }
+
+// checkCodecs decodes data into a T with both encoding/json and Unmarshal and checks that they agree. Values which
+// decode successfully must encode to the same JSON after being encoded and decoded again with either package. Empty
+// lists and objects are ignored when comparing since empty lists, such as Pricing.Services, are omitted and decode
+// as nil. It is used by the fuzz targets for each type with custom JSON methods.
+func checkCodecs[T any](t *testing.T, data []byte) {
+	t.Helper()
+	var std, v2 T
+	stdErr := stdjson.Unmarshal(data, &std)
+	v2Err := Unmarshal(data, &v2)
+	if !assert.Equal(t, stdErr == nil, v2Err == nil, "encoding/json error: %v, json v2 error: %v", stdErr, v2Err) || stdErr != nil {
+		return
+	}
+	assert.Equal(t, std, v2)
+
+	encoded, err := json.Marshal(v2)
+	require.NoError(t, err)
+	var decoded T
+	require.NoError(t, json.Unmarshal(encoded, &decoded), string(encoded))
+	reencoded, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, nonEmptyJSON(t, encoded), nonEmptyJSON(t, reencoded), string(encoded))
+
+	encoded, err = stdjson.Marshal(std)
+	require.NoError(t, err)
+	decoded = *new(T)
+	require.NoError(t, stdjson.Unmarshal(encoded, &decoded), string(encoded))
+	reencoded, err = stdjson.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, nonEmptyJSON(t, encoded), nonEmptyJSON(t, reencoded), string(encoded))
+}
+
+// nonEmptyJSON decodes data with the empty lists and objects within objects removed.
+func nonEmptyJSON(t *testing.T, data []byte) any {
+	t.Helper()
+	var v any
+	require.NoError(t, stdjson.Unmarshal(data, &v))
+	return removeEmpty(v)
+}
+
+func removeEmpty(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, member := range v {
+			v[name] = removeEmpty(member)
+			switch member := v[name].(type) {
+			case map[string]any:
+				if len(member) == 0 {
+					delete(v, name)
+				}
+			case []any:
+				if len(member) == 0 {
+					delete(v, name)
+				}
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = removeEmpty(v[i])
+		}
+	}
+	return v
+}
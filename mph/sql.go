@@ -0,0 +1,271 @@
+package mph
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+)
+
+// ListFormat is the format used to store lists of strings in a single database column.
+type ListFormat int
+
+const (
+	ListFormatJSON  ListFormat = iota // JSON array such as ["a","b"] (default)
+	ListFormatArray                   // PostgreSQL array literal such as {"a","b"}
+)
+
+// SQLList stores a list of strings in a single database column in Format. Use it to store EditReasons in a format
+// other than JSON. Lists in either format can be scanned into EditReasons, so the format can be changed without
+// migrating existing rows.
+type SQLList struct {
+	List   []string
+	Format ListFormat
+}
+
+var (
+	_ sql.Scanner   = &Date{}
+	_ driver.Valuer = Date{}
+	_ sql.Scanner   = &EditReasons{}
+	_ driver.Valuer = EditReasons{}
+	_ driver.Valuer = SQLList{}
+	_ sql.Scanner   = &PricedServices{}
+	_ driver.Valuer = PricedServices{}
+	_ sql.Scanner   = new(RuralIndicator)
+	_ driver.Valuer = RuralIndicator("")
+	_ sql.Scanner   = &PriceConfig{}
+	_ driver.Valuer = PriceConfig{}
+)
+
+// Scan reads a date from a time or from a string in any of the formats accepted by ParseDate. NULL is read as the
+// zero Date.
+func (d *Date) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = NewDate(v.Year(), int(v.Month()), v.Day())
+	case string, []byte:
+		parsed, err := ParseDate(asString(v))
+		if err != nil {
+			return errtrace.Wrap(err)
+		}
+		*d = parsed
+	default:
+		return errtrace.Errorf("cannot scan %T into Date", value)
+	}
+	return nil
+}
+
+// Value stores the date as a time at midnight UTC, or NULL if the date is not set.
+func (d Date) Value() (driver.Value, error) {
+	if d.Time.IsZero() {
+		return nil, nil
+	}
+	return NewDate(d.Time.Year(), int(d.Time.Month()), d.Time.Day()).Time, nil
+}
+
+// Scan reads a list stored as a JSON array or a PostgreSQL array literal. NULL and empty strings are read as nil.
+func (e *EditReasons) Scan(value any) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+	switch value.(type) {
+	case string, []byte:
+	default:
+		return errtrace.Errorf("cannot scan %T into EditReasons", value)
+	}
+	s := strings.TrimSpace(asString(value))
+	var reasons []string
+	var err error
+	switch {
+	case s == "":
+	case s[0] == '{':
+		reasons, err = parseArrayLiteral(s)
+	default:
+		err = json.Unmarshal([]byte(s), &reasons)
+	}
+	if err != nil {
+		return errtrace.Errorf("cannot scan %q into EditReasons: %w", s, err)
+	}
+	if len(reasons) == 0 {
+		reasons = nil
+	}
+	*e = reasons
+	return nil
+}
+
+// Value stores the list as a JSON array, or NULL if the list is empty. Use SQLList to store it in another format.
+func (e EditReasons) Value() (driver.Value, error) {
+	return errtrace.Wrap2(SQLList{List: e, Format: ListFormatJSON}.Value())
+}
+
+// Value stores the list in its format, or NULL if the list is empty.
+func (l SQLList) Value() (driver.Value, error) {
+	if len(l.List) == 0 {
+		return nil, nil
+	}
+	if l.Format == ListFormatArray {
+		return formatArrayLiteral(l.List), nil
+	}
+	data, err := json.Marshal(l.List)
+	return string(data), errtrace.Wrap(err)
+}
+
+// Scan reads services stored as a JSON array. NULL and empty strings are read as nil.
+func (p *PricedServices) Scan(value any) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	switch value.(type) {
+	case string, []byte:
+	default:
+		return errtrace.Errorf("cannot scan %T into PricedServices", value)
+	}
+	s := strings.TrimSpace(asString(value))
+	if s == "" {
+		*p = nil
+		return nil
+	}
+	var services []PricedService
+	if err := json.Unmarshal([]byte(s), &services, decodeOptions()); err != nil {
+		return errtrace.Wrap(err)
+	}
+	*p = services
+	return nil
+}
+
+// Value stores the services as a JSON array, or NULL if there are none.
+func (p PricedServices) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]PricedService(p))
+	return string(data), errtrace.Wrap(err)
+}
+
+// Scan reads a rural indicator stored as a string or as its legacy integer value. NULL is read as urban.
+func (r *RuralIndicator) Scan(value any) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		*r = RuralIndicatorUrban
+	case int64:
+		*r, err = ruralIndicatorFromInt(v)
+	case string, []byte:
+		*r, err = ruralIndicatorFromString(asString(v))
+	default:
+		err = errtrace.Errorf("cannot scan %T into RuralIndicator", value)
+	}
+	return errtrace.Wrap(err)
+}
+
+// Value stores the rural indicator as a string.
+func (r RuralIndicator) Value() (driver.Value, error) {
+	return string(r), nil
+}
+
+// Scan reads a configuration stored as a JSON object. NULL is read as the default configuration.
+func (c *PriceConfig) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*c = PriceConfig{}
+		return nil
+	case string, []byte:
+		var config PriceConfig
+		if err := json.Unmarshal([]byte(asString(v)), &config, decodeOptions()); err != nil {
+			return errtrace.Wrap(err)
+		}
+		*c = config
+		return nil
+	}
+	return errtrace.Errorf("cannot scan %T into PriceConfig", value)
+}
+
+// Value stores the configuration as a JSON object. Only the options which are set are included.
+func (c PriceConfig) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	return string(data), errtrace.Wrap(err)
+}
+
+// asString returns a string or []byte value as a string.
+func asString(value any) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value.(string)
+}
+
+// formatArrayLiteral formats values as a PostgreSQL array literal with each element quoted.
+func formatArrayLiteral(values []string) string {
+	var buf strings.Builder
+	buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('"')
+		for _, c := range []byte(v) {
+			if c == '"' || c == '\\' {
+				buf.WriteByte('\\')
+			}
+			buf.WriteByte(c)
+		}
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// parseArrayLiteral parses a one-dimensional PostgreSQL array literal. NULL elements are read as empty strings.
+func parseArrayLiteral(s string) ([]string, error) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, errtrace.Errorf("array literal must be enclosed in braces")
+	}
+	s = s[1 : len(s)-1]
+	var values []string
+	for i := 0; i < len(s); {
+		var value strings.Builder
+		if s[i] == '"' {
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errtrace.Errorf("unterminated quoted element")
+			}
+			i++
+		} else {
+			start := i
+			for i < len(s) && s[i] != ',' {
+				i++
+			}
+			element := strings.TrimSpace(s[start:i])
+			if strings.ContainsAny(element, `{}"\`) {
+				return nil, errtrace.Errorf("invalid element %q", element)
+			}
+			if !strings.EqualFold(element, "NULL") {
+				value.WriteString(element)
+			}
+		}
+		values = append(values, value.String())
+		if i < len(s) {
+			if s[i] != ',' {
+				return nil, errtrace.Errorf("expected , at offset %d", i+1)
+			}
+			i++
+			if i == len(s) {
+				return nil, errtrace.Errorf("trailing ,")
+			}
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,139 @@
+package mph
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripSQL stores value with its Value method and scans the result into dest.
+func roundTripSQL(t *testing.T, value driver.Valuer, dest sql.Scanner) driver.Value {
+	t.Helper()
+	stored, err := value.Value()
+	require.NoError(t, err)
+	require.NoError(t, dest.Scan(stored))
+	return stored
+}
+
+func TestDateSQL(t *testing.T) {
+	t.Parallel()
+	var d Date
+	stored := roundTripSQL(t, NewDate(2020, 2, 27), &d)
+	assert.Equal(t, time.Date(2020, 2, 27, 0, 0, 0, 0, time.UTC), stored)
+	assert.Equal(t, NewDate(2020, 2, 27), d)
+
+	stored = roundTripSQL(t, Date{}, &d)
+	assert.Nil(t, stored)
+	assert.Equal(t, Date{}, d)
+
+	require.NoError(t, d.Scan(time.Date(2020, 2, 27, 23, 0, 0, 0, time.FixedZone("EST", -5*60*60))))
+	assert.Equal(t, NewDate(2020, 2, 27), d)
+	require.NoError(t, d.Scan([]byte("2020-02-28")))
+	assert.Equal(t, NewDate(2020, 2, 28), d)
+	require.NoError(t, d.Scan("20200229"))
+	assert.Equal(t, NewDate(2020, 2, 29), d)
+	assert.Error(t, d.Scan("20200230"))
+	assert.Error(t, d.Scan(int64(20200227)))
+}
+
+func TestEditReasonsSQL(t *testing.T) {
+	t.Parallel()
+	reasons := EditReasons{"invalid code", `quoted "reason"`, `back\slash`, "comma, separated"}
+	var scanned EditReasons
+	stored := roundTripSQL(t, reasons, &scanned)
+	assert.Equal(t, `["invalid code","quoted \"reason\"","back\\slash","comma, separated"]`, stored)
+	assert.Equal(t, reasons, scanned)
+
+	stored = roundTripSQL(t, EditReasons{}, &scanned)
+	assert.Nil(t, stored)
+	assert.Nil(t, scanned)
+
+	for input, expected := range map[string]EditReasons{
+		`{"a","b"}`:          {"a", "b"},
+		`{a, b ,NULL}`:       {"a", "b", ""},
+		`{}`:                 nil,
+		`{"a\"b","c\\d",e}`:  {`a"b`, `c\d`, "e"},
+		`[]`:                 nil,
+		`  ["a"] `:           {"a"},
+		``:                   nil,
+		`{"with,comma","x"}`: {"with,comma", "x"},
+	} {
+		require.NoError(t, scanned.Scan([]byte(input)), input)
+		assert.Equal(t, expected, scanned, input)
+	}
+	for _, input := range []any{`{"a"`, `{"a`, `{a,}`, `{"a" "b"}`, `{{a}}`, `["a"`, int64(1)} {
+		assert.Error(t, scanned.Scan(input), input)
+	}
+}
+
+func TestEditReasonsSQLArrayFormat(t *testing.T) {
+	t.Parallel()
+	reasons := EditReasons{"invalid code", `quoted "reason"`, `back\slash`, "comma, separated"}
+	var scanned EditReasons
+	stored := roundTripSQL(t, SQLList{List: reasons, Format: ListFormatArray}, &scanned)
+	assert.Equal(t, `{"invalid code","quoted \"reason\"","back\\slash","comma, separated"}`, stored)
+	assert.Equal(t, reasons, scanned)
+
+	stored = roundTripSQL(t, SQLList{List: reasons, Format: ListFormatJSON}, &scanned)
+	assert.Equal(t, `["invalid code","quoted \"reason\"","back\\slash","comma, separated"]`, stored)
+	assert.Equal(t, reasons, scanned)
+	assert.Nil(t, roundTripSQL(t, SQLList{Format: ListFormatArray}, &scanned))
+	assert.Nil(t, scanned)
+
+	// lists stored as JSON can still be read
+	require.NoError(t, scanned.Scan(`["a","b"]`))
+	assert.Equal(t, EditReasons{"a", "b"}, scanned)
+}
+
+func TestPricedServicesSQL(t *testing.T) {
+	t.Parallel()
+	services := PricedServices{
+		{LineNumber: "1", MedicareAmount: 100.5, MedicareRepricingCode: LineRepricingCode("MPFS"), EditDetail: &LineEdits{ProcedureEdits: EditReasons{"edit"}}},
+		{LineNumber: "2", ProviderDetail: &ProviderDetail{CCN: "010001", RuralIndicator: RuralIndicatorRural}},
+	}
+	var scanned PricedServices
+	roundTripSQL(t, services, &scanned)
+	assert.Equal(t, services, scanned)
+
+	assert.Nil(t, roundTripSQL(t, PricedServices(nil), &scanned))
+	assert.Nil(t, scanned)
+	assert.Error(t, scanned.Scan(`{"lineNumber":"1"}`))
+	assert.Error(t, scanned.Scan(1.5))
+}
+
+func TestRuralIndicatorSQL(t *testing.T) {
+	t.Parallel()
+	var r RuralIndicator
+	assert.Equal(t, "R", roundTripSQL(t, RuralIndicatorRural, &r))
+	assert.Equal(t, RuralIndicatorRural, r)
+	assert.Equal(t, "", roundTripSQL(t, RuralIndicatorUrban, &r))
+	assert.Equal(t, RuralIndicatorUrban, r)
+
+	require.NoError(t, r.Scan(int64(66)))
+	assert.Equal(t, RuralIndicatorSuperRural, r)
+	require.NoError(t, r.Scan([]byte("R")))
+	assert.Equal(t, RuralIndicatorRural, r)
+	require.NoError(t, r.Scan(nil))
+	assert.Equal(t, RuralIndicatorUrban, r)
+	assert.Error(t, r.Scan("X"))
+	assert.Error(t, r.Scan(int64(1)))
+	assert.Error(t, r.Scan(true))
+}
+
+func TestPriceConfigSQL(t *testing.T) {
+	t.Parallel()
+	config := PriceConfig{IsCommercial: true, OverrideThreshold: 300, ContractRuleset: "acme"}
+	var scanned PriceConfig
+	stored := roundTripSQL(t, config, &scanned)
+	assert.Equal(t, `{"contractRuleset":"acme","isCommercial":true,"overrideThreshold":300}`, stored)
+	assert.Equal(t, config, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Equal(t, PriceConfig{}, scanned)
+	assert.Error(t, scanned.Scan("[]"))
+	assert.Error(t, scanned.Scan(int64(1)))
+}
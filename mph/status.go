@@ -0,0 +1,214 @@
+package mph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidTransition  = errors.New("invalid claim status transition")
+	ErrUnknownClaimStatus = errors.New("unknown claim status")
+)
+
+// TransitionError is returned when a claim cannot move from one status to another. It matches ErrInvalidTransition
+// or ErrUnknownClaimStatus when used with errors.Is.
+type TransitionError struct {
+	From ClaimStatus
+	To   ClaimStatus
+	Err  error // ErrInvalidTransition or ErrUnknownClaimStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s from %q to %q", e.Err, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+// ClaimStatuses lists every claim status in processing order.
+var ClaimStatuses = []ClaimStatus{
+	StatusNew,
+	StatusReceived,
+	StatusPendingClaimInputValidation,
+	StatusInputValidated,
+	StatusPendingProviderMatching,
+	StatusProviderMatched,
+	StatusPendingClaimEditReview,
+	StatusEditComplete,
+	StatusPendingMedicareCalculation,
+	StatusPendingMedicareReview,
+	StatusMedicarePriced,
+	StatusPendingPrimaryAllowedDetermination,
+	StatusPendingPrimaryAllowedReview,
+	StatusPrimaryAllowedPriced,
+	StatusPendingNetworkAllowedDetermination,
+	StatusPendingNetworkAllowedReview,
+	StatusNetworkAllowedPriced,
+	StatusOutOfNetwork,
+	StatusRequestMoreInfo,
+	StatusDenied,
+	StatusPriced,
+	StatusHeld,
+	StatusError,
+	StatusReturned,
+}
+
+// claimStatusTransitions lists the statuses a claim may move to from each status. Any non-terminal status may also
+// move to Held or Error, which is added by ClaimStatus.Next.
+var claimStatusTransitions = map[ClaimStatus][]ClaimStatus{
+	StatusNew:                                {StatusReceived},
+	StatusReceived:                           {StatusPendingClaimInputValidation, StatusInputValidated, StatusDenied, StatusReturned},
+	StatusPendingClaimInputValidation:        {StatusInputValidated, StatusRequestMoreInfo},
+	StatusInputValidated:                     {StatusPendingProviderMatching, StatusProviderMatched},
+	StatusPendingProviderMatching:            {StatusProviderMatched, StatusOutOfNetwork, StatusRequestMoreInfo},
+	StatusProviderMatched:                    {StatusPendingClaimEditReview, StatusEditComplete},
+	StatusPendingClaimEditReview:             {StatusEditComplete, StatusDenied, StatusRequestMoreInfo},
+	StatusEditComplete:                       {StatusPendingMedicareCalculation, StatusMedicarePriced},
+	StatusPendingMedicareCalculation:         {StatusPendingMedicareReview, StatusMedicarePriced},
+	StatusPendingMedicareReview:              {StatusMedicarePriced, StatusRequestMoreInfo},
+	StatusMedicarePriced:                     {StatusPendingMedicareReview, StatusPendingPrimaryAllowedDetermination, StatusPrimaryAllowedPriced, StatusPriced},
+	StatusPendingPrimaryAllowedDetermination: {StatusPendingPrimaryAllowedReview, StatusPrimaryAllowedPriced, StatusOutOfNetwork},
+	StatusPendingPrimaryAllowedReview:        {StatusPrimaryAllowedPriced, StatusRequestMoreInfo},
+	StatusPrimaryAllowedPriced:               {StatusPendingPrimaryAllowedReview, StatusPendingNetworkAllowedDetermination, StatusNetworkAllowedPriced, StatusPriced},
+	StatusPendingNetworkAllowedDetermination: {StatusPendingNetworkAllowedReview, StatusNetworkAllowedPriced, StatusOutOfNetwork},
+	StatusPendingNetworkAllowedReview:        {StatusNetworkAllowedPriced, StatusRequestMoreInfo},
+	StatusNetworkAllowedPriced:               {StatusPendingNetworkAllowedReview, StatusPriced},
+	StatusOutOfNetwork:                       {StatusPriced, StatusReturned},
+	StatusRequestMoreInfo:                    {StatusReceived, StatusReturned},
+	StatusDenied:                             {StatusReturned},
+	StatusPriced:                             {StatusReturned},
+	StatusHeld:                               {StatusReceived, StatusDenied, StatusReturned},
+	StatusError:                              {StatusReceived, StatusReturned},
+	StatusReturned:                           nil,
+}
+
+// IsKnown returns true if the status is one of ClaimStatuses.
+func (s ClaimStatus) IsKnown() bool {
+	_, ok := claimStatusTransitions[s]
+	return ok
+}
+
+// IsTerminal returns true if no further processing can happen after the status.
+func (s ClaimStatus) IsTerminal() bool {
+	return s == StatusReturned
+}
+
+// IsPending returns true if the claim is waiting on a pending step.
+func (s ClaimStatus) IsPending() bool {
+	return s.Step == StepPending
+}
+
+// Next returns the statuses a claim may move to from s.
+func (s ClaimStatus) Next() []ClaimStatus {
+	next := claimStatusTransitions[s]
+	if s.IsTerminal() || !s.IsKnown() {
+		return nil
+	}
+	result := append([]ClaimStatus(nil), next...)
+	if s != StatusHeld {
+		result = append(result, StatusHeld)
+	}
+	if s != StatusError {
+		result = append(result, StatusError)
+	}
+	return result
+}
+
+// CanTransition returns true if a claim may move from s to the given status.
+func (s ClaimStatus) CanTransition(to ClaimStatus) bool {
+	for _, next := range s.Next() {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ClaimStateMachine tracks the status of a single claim and enforces the allowed transitions between statuses.
+// A held claim may also be released back to the status it was in when it was held.
+type ClaimStateMachine struct {
+	status   ClaimStatus
+	heldFrom ClaimStatus
+}
+
+// NewClaimStateMachine creates a state machine starting at the given status. An empty status starts at StatusNew.
+func NewClaimStateMachine(status ClaimStatus) (*ClaimStateMachine, error) {
+	if status.IsEmpty() {
+		status = StatusNew
+	}
+	if !status.IsKnown() {
+		return nil, &TransitionError{To: status, Err: ErrUnknownClaimStatus}
+	}
+	return &ClaimStateMachine{status: status}, nil
+}
+
+// Status returns the current status.
+func (m *ClaimStateMachine) Status() ClaimStatus {
+	return m.status
+}
+
+// Next returns the statuses the claim may move to from its current status.
+func (m *ClaimStateMachine) Next() []ClaimStatus {
+	next := m.status.Next()
+	if m.status == StatusHeld && !m.heldFrom.IsEmpty() && !m.status.CanTransition(m.heldFrom) {
+		next = append(next, m.heldFrom)
+	}
+	return next
+}
+
+// CanTransition returns true if the claim may move from its current status to the given status.
+func (m *ClaimStateMachine) CanTransition(to ClaimStatus) bool {
+	for _, next := range m.Next() {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the claim to the given status. It returns a *TransitionError if the transition is not allowed.
+func (m *ClaimStateMachine) Transition(to ClaimStatus) error {
+	if !to.IsKnown() {
+		return &TransitionError{From: m.status, To: to, Err: ErrUnknownClaimStatus}
+	}
+	if !m.CanTransition(to) {
+		return &TransitionError{From: m.status, To: to, Err: ErrInvalidTransition}
+	}
+	if to == StatusHeld {
+		m.heldFrom = m.status
+	} else {
+		m.heldFrom = ClaimStatus{}
+	}
+	m.status = to
+	return nil
+}
+
+// ClaimStatusDOT renders the claim status transitions as a Graphviz DOT graph. Transitions to Held and Error,
+// which are allowed from every non-terminal status, are omitted to keep the graph readable.
+func ClaimStatusDOT() string {
+	var buf strings.Builder
+	buf.WriteString("digraph ClaimStatus {\n")
+	buf.WriteString("\trankdir=LR;\n")
+	buf.WriteString("\tnode [shape=box, style=rounded];\n")
+	for _, s := range ClaimStatuses {
+		attributes := ""
+		switch {
+		case s.IsTerminal():
+			attributes = ", peripheries=2"
+		case s.IsPending():
+			attributes = `, style="rounded,dashed"`
+		case s == StatusHeld || s == StatusError:
+			attributes = `, style="rounded,filled", fillcolor=lightgrey`
+		}
+		fmt.Fprintf(&buf, "\t%q [label=%q%s];\n", s.String(), s.String(), attributes)
+	}
+	for _, s := range ClaimStatuses {
+		for _, next := range claimStatusTransitions[s] {
+			fmt.Fprintf(&buf, "\t%q -> %q;\n", s.String(), next.String())
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -0,0 +1,83 @@
+package mph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimStatusTransitions(t *testing.T) {
+	t.Parallel()
+	assert.Len(t, claimStatusTransitions, len(ClaimStatuses), "every status should have transitions defined")
+	for _, s := range ClaimStatuses {
+		assert.True(t, s.IsKnown(), s.String())
+		for _, next := range s.Next() {
+			assert.True(t, next.IsKnown(), "%s -> %s", s, next)
+		}
+		if !s.IsTerminal() {
+			assert.NotEmpty(t, s.Next(), s.String())
+			assert.True(t, s == StatusHeld || s.CanTransition(StatusHeld), s.String())
+			assert.True(t, s == StatusError || s.CanTransition(StatusError), s.String())
+		}
+	}
+
+	assert.True(t, StatusNew.CanTransition(StatusReceived))
+	assert.True(t, StatusPendingMedicareCalculation.CanTransition(StatusMedicarePriced))
+	assert.False(t, StatusNew.CanTransition(StatusPriced))
+	assert.False(t, StatusPriced.CanTransition(StatusMedicarePriced))
+	assert.Empty(t, StatusReturned.Next())
+	assert.Empty(t, ClaimStatus{Step: "Unknown"}.Next())
+	assert.True(t, StatusPendingMedicareReview.IsPending())
+	assert.False(t, StatusMedicarePriced.IsPending())
+}
+
+func TestClaimStateMachine(t *testing.T) {
+	t.Parallel()
+	m, err := NewClaimStateMachine(ClaimStatus{})
+	require.NoError(t, err)
+	assert.Equal(t, StatusNew, m.Status())
+
+	for _, to := range []ClaimStatus{StatusReceived, StatusInputValidated, StatusProviderMatched, StatusEditComplete, StatusMedicarePriced} {
+		require.NoError(t, m.Transition(to))
+	}
+	assert.Equal(t, StatusMedicarePriced, m.Status())
+
+	err = m.Transition(StatusNew)
+	var transitionErr *TransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+	assert.Equal(t, StatusMedicarePriced, transitionErr.From)
+	assert.Equal(t, StatusNew, transitionErr.To)
+	assert.Equal(t, `invalid claim status transition from "Medicare Priced" to "New"`, err.Error())
+	assert.Equal(t, StatusMedicarePriced, m.Status(), "status should not change after an invalid transition")
+
+	err = m.Transition(ClaimStatus{Step: "Unknown"})
+	assert.ErrorIs(t, err, ErrUnknownClaimStatus)
+
+	// a held claim can be released back to the status it was held from
+	require.NoError(t, m.Transition(StatusHeld))
+	assert.True(t, m.CanTransition(StatusMedicarePriced))
+	assert.False(t, m.CanTransition(StatusPrimaryAllowedPriced))
+	require.NoError(t, m.Transition(StatusMedicarePriced))
+	require.NoError(t, m.Transition(StatusPriced))
+	require.NoError(t, m.Transition(StatusReturned))
+	assert.Empty(t, m.Next())
+
+	_, err = NewClaimStateMachine(ClaimStatus{Step: "Unknown"})
+	assert.ErrorIs(t, err, ErrUnknownClaimStatus)
+}
+
+func TestClaimStatusDOT(t *testing.T) {
+	t.Parallel()
+	dot := ClaimStatusDOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph ClaimStatus {\n"))
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+	assert.Contains(t, dot, "\t\"New\" -> \"Received\";\n")
+	assert.Contains(t, dot, "\t\"Pending Medicare Calculation\" [label=\"Pending Medicare Calculation\", style=\"rounded,dashed\"];\n")
+	assert.Contains(t, dot, "\t\"Returned\" [label=\"Returned\", peripheries=2];\n")
+	assert.NotContains(t, dot, "-> \"Held\"")
+	assert.Equal(t, dot, ClaimStatusDOT(), "output should be deterministic")
+}
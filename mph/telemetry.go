@@ -0,0 +1,165 @@
+package mph
+
+import (
+	"context"
+)
+
+// Metric names recorded by the Client.
+const (
+	MetricClaimsPriced    = "mph.client.claims.priced"    // counter of successfully priced claims by endpoint and Medicare source
+	MetricErrors          = "mph.client.errors"           // counter of request and claim errors by endpoint and error title
+	MetricRequestDuration = "mph.client.request.duration" // histogram of request latency in seconds by endpoint and status code
+)
+
+// Attribute keys attached to spans and measurements recorded by the Client.
+const (
+	AttributeEndpoint       = "mph.endpoint"
+	AttributeStatusCode     = "http.response.status_code"
+	AttributeClaimCount     = "mph.claim.count"
+	AttributeSuccessCount   = "mph.claim.success_count"
+	AttributeErrorCount     = "mph.claim.error_count"
+	AttributeErrorTitle     = "mph.error.title"
+	AttributeMedicareSource = "mph.medicare_source"
+)
+
+// Attribute is a key value pair attached to a span or measurement. Value is a string, bool, int, int64 or float64.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Tracer starts spans. It is a small subset of the OpenTelemetry tracing API so that the core module does not
+// depend on OpenTelemetry. See the mphotel module for an adapter.
+type Tracer interface {
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// Span is a single operation started by a Tracer.
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Meter records measurements. Add is used for counters and Record for histograms. Instruments are identified by
+// name (e.g. MetricClaimsPriced).
+type Meter interface {
+	Add(ctx context.Context, name string, value int64, attributes ...Attribute)
+	Record(ctx context.Context, name string, value float64, attributes ...Attribute)
+}
+
+// NoopTracer is a Tracer which does nothing. It is used by the Client when no Tracer is set.
+type NoopTracer struct{}
+
+// NoopSpan is a Span which does nothing.
+type NoopSpan struct{}
+
+// NoopMeter is a Meter which does nothing. It is used by the Client when no Meter is set.
+type NoopMeter struct{}
+
+var (
+	_ Tracer = NoopTracer{}
+	_ Span   = NoopSpan{}
+	_ Meter  = NoopMeter{}
+)
+
+func (NoopTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	return ctx, NoopSpan{}
+}
+
+func (NoopSpan) SetAttributes(attributes ...Attribute) {}
+func (NoopSpan) RecordError(err error)                 {}
+func (NoopSpan) End()                                  {}
+
+func (NoopMeter) Add(ctx context.Context, name string, value int64, attributes ...Attribute)      {}
+func (NoopMeter) Record(ctx context.Context, name string, value float64, attributes ...Attribute) {}
+
+// SetTracer sets the Tracer used to start a span around every request. A nil Tracer disables tracing.
+// SetTracer is not safe to call concurrently with requests.
+func (c *Client) SetTracer(t Tracer) *Client {
+	c.tracer = t
+	return c
+}
+
+// SetMeter sets the Meter used to record metrics for every request. A nil Meter disables metrics.
+// SetMeter is not safe to call concurrently with requests.
+func (c *Client) SetMeter(m Meter) *Client {
+	c.meter = m
+	return c
+}
+
+func (c *Client) getTracer() Tracer {
+	if c.tracer == nil {
+		return NoopTracer{}
+	}
+	return c.tracer
+}
+
+func (c *Client) getMeter() Meter {
+	if c.meter == nil {
+		return NoopMeter{}
+	}
+	return c.meter
+}
+
+// startSpan starts the span for a call.
+func (c *Client) startSpan(ctx context.Context, call *Call) (context.Context, Span) {
+	return c.getTracer().Start(ctx, "POST "+call.Path, Attribute{AttributeEndpoint, call.Path}, Attribute{AttributeClaimCount, call.Count})
+}
+
+// outcomeSummary counts the claims which succeeded and failed in an Outcome.
+type outcomeSummary struct {
+	err       *ResponseError            // error for the whole request
+	results   []ErrorAndResult[Pricing] // results for each claim
+	successes int
+	failures  int
+}
+
+func summarize(call *Call, outcome Outcome) outcomeSummary {
+	var summary outcomeSummary
+	switch response := outcome.Response.(type) {
+	case *Response[Pricing]:
+		summary.err = response.Error
+		if summary.err == nil {
+			summary.results = []ErrorAndResult[Pricing]{{Result: response.Result}}
+		}
+	case *ErrorAndResultResponses[Pricing]:
+		summary.err = response.Error
+		summary.results = response.Results
+	}
+	if summary.err != nil {
+		summary.failures = call.Count
+	}
+	for _, result := range summary.results {
+		if result.Error != nil {
+			summary.failures++
+		} else {
+			summary.successes++
+		}
+	}
+	return summary
+}
+
+// observe records the outcome of a call on its span and meter.
+func (c *Client) observe(ctx context.Context, span Span, call *Call, outcome Outcome, summary outcomeSummary) {
+	meter := c.getMeter()
+	endpoint := Attribute{AttributeEndpoint, call.Path}
+	meter.Record(ctx, MetricRequestDuration, outcome.Duration.Seconds(), endpoint, Attribute{AttributeStatusCode, outcome.StatusCode})
+
+	if summary.err != nil {
+		meter.Add(ctx, MetricErrors, 1, endpoint, Attribute{AttributeErrorTitle, summary.err.Title})
+		span.RecordError(RedactError(summary.err))
+	}
+	for _, result := range summary.results {
+		if result.Error != nil {
+			meter.Add(ctx, MetricErrors, 1, endpoint, Attribute{AttributeErrorTitle, result.Error.Title})
+		} else {
+			meter.Add(ctx, MetricClaimsPriced, 1, endpoint, Attribute{AttributeMedicareSource, string(result.Result.MedicareSource)})
+		}
+	}
+	span.SetAttributes(
+		Attribute{AttributeStatusCode, outcome.StatusCode},
+		Attribute{AttributeSuccessCount, summary.successes},
+		Attribute{AttributeErrorCount, summary.failures},
+	)
+}
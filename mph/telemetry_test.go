@@ -0,0 +1,148 @@
+package mph
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	errs       []error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttributes(attributes ...Attribute) {
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	span := &recordedSpan{name: name, attributes: map[string]any{}}
+	span.SetAttributes(attributes...)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+type measurement struct {
+	name       string
+	value      float64
+	attributes map[string]any
+}
+
+type recordingMeter struct {
+	mu           sync.Mutex
+	measurements []measurement
+}
+
+func (m *recordingMeter) record(name string, value float64, attributes []Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attrs := map[string]any{}
+	for _, a := range attributes {
+		attrs[a.Key] = a.Value
+	}
+	m.measurements = append(m.measurements, measurement{name, value, attrs})
+}
+
+func (m *recordingMeter) Add(ctx context.Context, name string, value int64, attributes ...Attribute) {
+	m.record(name, float64(value), attributes)
+}
+
+func (m *recordingMeter) Record(ctx context.Context, name string, value float64, attributes ...Attribute) {
+	m.record(name, value, attributes)
+}
+
+// named returns the measurements with the given name.
+func (m *recordingMeter) named(name string) []measurement {
+	var measurements []measurement
+	for _, measurement := range m.measurements {
+		if measurement.name == name {
+			measurements = append(measurements, measurement)
+		}
+	}
+	return measurements
+}
+
+// sourcePricer prices claims with a Medicare source and fails claims without a claim ID.
+type sourcePricer struct {
+	stubPricer
+}
+
+func (p *sourcePricer) PriceBatch(ctx context.Context, config PriceConfig, inputs ...Claim) ErrorAndResultResponses[Pricing] {
+	responses := p.stubPricer.PriceBatch(ctx, config, inputs...)
+	for i := range responses.Results {
+		if responses.Results[i].Error == nil {
+			responses.Results[i].Result.MedicareSource = MedicareSourceInpatient
+		}
+	}
+	return responses
+}
+
+func TestClientTelemetry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tracer := &recordingTracer{}
+	meter := &recordingMeter{}
+	client := NewClient(handlerDoer{NewHandler(&sourcePricer{})}, true, "key").SetTracer(tracer).SetMeter(meter)
+
+	client.PriceBatch(ctx, PriceConfig{}, Claim{ClaimID: "1", BilledAmount: 100}, Claim{}, Claim{ClaimID: "3"})
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, "POST /v1/medicare/price/claims", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, map[string]any{
+		AttributeEndpoint:     "/v1/medicare/price/claims",
+		AttributeClaimCount:   3,
+		AttributeStatusCode:   http.StatusOK,
+		AttributeSuccessCount: 2,
+		AttributeErrorCount:   1,
+	}, span.attributes)
+	assert.Empty(t, span.errs)
+
+	durations := meter.named(MetricRequestDuration)
+	require.Len(t, durations, 1)
+	assert.Positive(t, durations[0].value)
+	assert.Equal(t, map[string]any{AttributeEndpoint: "/v1/medicare/price/claims", AttributeStatusCode: http.StatusOK}, durations[0].attributes)
+
+	priced := meter.named(MetricClaimsPriced)
+	require.Len(t, priced, 2)
+	assert.Equal(t, string(MedicareSourceInpatient), priced[0].attributes[AttributeMedicareSource])
+
+	errs := meter.named(MetricErrors)
+	require.Len(t, errs, 1)
+	assert.Equal(t, PriceErrorTitle, errs[0].attributes[AttributeErrorTitle])
+
+	client.EstimateRateSheet(ctx, RateSheet{})
+	require.Len(t, tracer.spans, 2)
+	span = tracer.spans[1]
+	require.Len(t, span.errs, 1)
+	assert.Equal(t, "not supported (status 501)", span.errs[0].Error())
+	assert.Equal(t, 1, span.attributes[AttributeErrorCount])
+	assert.Equal(t, http.StatusNotImplemented, span.attributes[AttributeStatusCode])
+	errs = meter.named(MetricErrors)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "not supported", errs[1].attributes[AttributeErrorTitle])
+	assert.Equal(t, "/v1/medicare/estimate/rate-sheet", errs[1].attributes[AttributeEndpoint])
+}
+
+func TestClientTelemetryNoop(t *testing.T) {
+	t.Parallel()
+	client := NewClient(handlerDoer{NewHandler(&stubPricer{})}, true, "key").SetTracer(nil).SetMeter(nil)
+	response := client.Price(context.Background(), PriceConfig{}, Claim{ClaimID: "1", BilledAmount: 100})
+	require.Nil(t, response.Error)
+	assert.Equal(t, 100.0, response.Result.MedicareAmount)
+}
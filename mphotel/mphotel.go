@@ -0,0 +1,132 @@
+// Package mphotel adapts OpenTelemetry tracer and meter providers to the mph.Tracer and mph.Meter interfaces.
+// It is a separate module so that the core mph module does not depend on OpenTelemetry.
+package mphotel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer and meter created by this package.
+const InstrumentationName = "github.com/mypricehealth/mphgo/mph"
+
+// Tracer is an mph.Tracer which starts OpenTelemetry client spans.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ mph.Tracer = &Tracer{}
+
+// NewTracer returns a Tracer using tp (e.g. otel.GetTracerProvider()).
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: tp.Tracer(InstrumentationName)}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...mph.Attribute) (context.Context, mph.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(convert(attributes)...))
+	return ctx, otelSpan{span}
+}
+
+// otelSpan adapts an OpenTelemetry span to mph.Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) SetAttributes(attributes ...mph.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+// RecordError records the error using mph.RedactError so that the details of problem documents, which may
+// describe the claim, are not exported.
+func (s otelSpan) RecordError(err error) {
+	err = mph.RedactError(err)
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s otelSpan) End() {
+	s.span.End()
+}
+
+// Meter is an mph.Meter which records to OpenTelemetry counters and histograms. Instruments are created the first
+// time a name is used.
+type Meter struct {
+	meter      metric.Meter
+	mu         sync.Mutex
+	counters   map[string]metric.Int64Counter
+	histograms map[string]metric.Float64Histogram
+}
+
+var _ mph.Meter = &Meter{}
+
+// NewMeter returns a Meter using mp (e.g. otel.GetMeterProvider()).
+func NewMeter(mp metric.MeterProvider) *Meter {
+	return &Meter{
+		meter:      mp.Meter(InstrumentationName),
+		counters:   map[string]metric.Int64Counter{},
+		histograms: map[string]metric.Float64Histogram{},
+	}
+}
+
+func (m *Meter) Add(ctx context.Context, name string, value int64, attributes ...mph.Attribute) {
+	m.mu.Lock()
+	counter, ok := m.counters[name]
+	if !ok {
+		var err error
+		if counter, err = m.meter.Int64Counter(name); err != nil {
+			m.mu.Unlock()
+			return
+		}
+		m.counters[name] = counter
+	}
+	m.mu.Unlock()
+	counter.Add(ctx, value, metric.WithAttributes(convert(attributes)...))
+}
+
+func (m *Meter) Record(ctx context.Context, name string, value float64, attributes ...mph.Attribute) {
+	m.mu.Lock()
+	histogram, ok := m.histograms[name]
+	if !ok {
+		var opts []metric.Float64HistogramOption
+		if name == mph.MetricRequestDuration {
+			opts = append(opts, metric.WithUnit("s"))
+		}
+		var err error
+		if histogram, err = m.meter.Float64Histogram(name, opts...); err != nil {
+			m.mu.Unlock()
+			return
+		}
+		m.histograms[name] = histogram
+	}
+	m.mu.Unlock()
+	histogram.Record(ctx, value, metric.WithAttributes(convert(attributes)...))
+}
+
+// convert converts mph attributes to OpenTelemetry attributes. Unsupported values are formatted as strings.
+func convert(attributes []mph.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch v := a.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(a.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(a.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(a.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(a.Key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(a.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,94 @@
+package mphotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, span := tracer.Start(context.Background(), "POST /v1/medicare/price/claim", mph.Attribute{Key: mph.AttributeEndpoint, Value: "/v1/medicare/price/claim"})
+	span.SetAttributes(mph.Attribute{Key: mph.AttributeStatusCode, Value: 422}, mph.Attribute{Key: "other", Value: []int{1}})
+	span.RecordError(errors.New("pricing failed"))
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST /v1/medicare/price/claim", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String(mph.AttributeEndpoint, "/v1/medicare/price/claim"),
+		attribute.Int(mph.AttributeStatusCode, 422),
+		attribute.String("other", "[1]"),
+	}, spans[0].Attributes())
+	require.Len(t, spans[0].Events(), 1)
+}
+
+func TestTracerRedactsErrors(t *testing.T) {
+	t.Parallel()
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	_, span := tracer.Start(context.Background(), "POST /v1/medicare/price/claim")
+	span.RecordError(&mph.ResponseError{Title: "invalid claim", Status: 400, Detail: "patient Jane Doe was born after the service date"})
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "invalid claim (status 400)", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	for _, attr := range spans[0].Events()[0].Attributes {
+		assert.NotContains(t, attr.Value.Emit(), "Jane Doe")
+	}
+}
+
+func TestMeter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	meter := NewMeter(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	source := mph.Attribute{Key: mph.AttributeMedicareSource, Value: string(mph.MedicareSourceInpatient)}
+	meter.Add(ctx, mph.MetricClaimsPriced, 1, source)
+	meter.Add(ctx, mph.MetricClaimsPriced, 2, source)
+	meter.Record(ctx, mph.MetricRequestDuration, 0.25, mph.Attribute{Key: mph.AttributeEndpoint, Value: "/v1/medicare/price/claims"})
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	assert.Equal(t, InstrumentationName, data.ScopeMetrics[0].Scope.Name)
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	sum, ok := metrics[mph.MetricClaimsPriced].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(3), sum.DataPoints[0].Value)
+	value, _ := sum.DataPoints[0].Attributes.Value(mph.AttributeMedicareSource)
+	assert.Equal(t, string(mph.MedicareSourceInpatient), value.AsString())
+
+	assert.Equal(t, "s", metrics[mph.MetricRequestDuration].Unit)
+	histogram, ok := metrics[mph.MetricRequestDuration].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+	assert.Equal(t, 0.25, histogram.DataPoints[0].Sum)
+}
@@ -0,0 +1,261 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/mypricehealth/decimal"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// rootTypes are the request and response types of the API. The types they use are defined along with them.
+var rootTypes = []reflect.Type{
+	reflect.TypeFor[mph.Claim](),
+	reflect.TypeFor[mph.RateSheet](),
+	reflect.TypeFor[mph.PriceConfig](),
+	reflect.TypeFor[mph.Pricing](),
+	reflect.TypeFor[mph.Response[mph.Pricing]](),
+	reflect.TypeFor[mph.ErrorAndResultResponses[mph.Pricing]](),
+}
+
+var (
+	dateType          = reflect.TypeFor[mph.Date]()
+	decimalType       = reflect.TypeFor[decimal.Decimal]()
+	jsontextValueType = reflect.TypeFor[jsontext.Value]()
+)
+
+// enums holds the values of the code types of the mph package.
+var enums = map[reflect.Type]enum{
+	reflect.TypeFor[mph.ClaimRepricingCode](): codeValues[mph.ClaimRepricingCode](),
+	reflect.TypeFor[mph.LineRepricingCode]():  codeValues[mph.LineRepricingCode](),
+	reflect.TypeFor[mph.MedicareSource]():     codeValues[mph.MedicareSource](),
+	reflect.TypeFor[mph.HospitalType]():       codeValues[mph.HospitalType](),
+	reflect.TypeFor[mph.FormType]():           codeValues[mph.FormType](),
+	reflect.TypeFor[mph.BillTypeSequence]():   codeValues[mph.BillTypeSequence](),
+	reflect.TypeFor[mph.SexType]():            codeValues[mph.SexType](),
+}
+
+// enum is the valid values of a code type and a list item describing each of them.
+type enum struct {
+	values       []any
+	descriptions []string
+}
+
+// code is implemented by the code types of the mph package.
+type code interface {
+	Description() string
+}
+
+func codeValues[T code, P interface {
+	*T
+	Values() []T
+}]() enum {
+	var e enum
+	for _, v := range P(nil).Values() {
+		e.values = append(e.values, v)
+		e.descriptions = append(e.descriptions, fmt.Sprintf("- `%v`: %s", v, v.Description()))
+	}
+	return e
+}
+
+// errorAndResultTags are the JSON tags of ErrorAndResult, which is encoded using an unexported type with the same
+// fields. Generating a schema fails if a field of ErrorAndResult is missing from the map.
+var errorAndResultTags = map[string]string{
+	"Error":       "error,omitzero",
+	"Result":      ",inline",
+	"ClaimStatus": "claimStatus,omitzero",
+}
+
+// schemaObject is a JSON Schema. Only the keywords needed to describe the API types are supported.
+type schemaObject struct {
+	Ref                  string        `json:"$ref,omitzero"`
+	Type                 string        `json:"type,omitzero"`
+	Description          string        `json:"description,omitzero"`
+	Pattern              string        `json:"pattern,omitzero"`
+	Enum                 []any         `json:"enum,omitzero"`
+	Items                *schemaObject `json:"items,omitzero"`
+	Properties           properties    `json:"properties,omitzero"`
+	AdditionalProperties *schemaObject `json:"additionalProperties,omitzero"` // an empty schema allows any value
+}
+
+// property is a member of an object schema.
+type property struct {
+	name   string
+	schema *schemaObject
+}
+
+// properties are encoded as an object with the members in the order of the struct fields.
+type properties []property
+
+func (p properties) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
+		return errtrace.Wrap(err)
+	}
+	for _, prop := range p {
+		if err := enc.WriteToken(jsontext.String(prop.name)); err != nil {
+			return errtrace.Wrap(err)
+		}
+		if err := json.MarshalEncode(enc, prop.schema); err != nil {
+			return errtrace.Wrap(err)
+		}
+	}
+	return errtrace.Wrap(enc.WriteToken(jsontext.EndObject))
+}
+
+// generator builds schemas of Go types. Structs and enums are added to defs and referenced with refPrefix.
+type generator struct {
+	comments  comments
+	refPrefix string
+	defs      map[string]*schemaObject
+	err       error
+}
+
+func newGenerator(comments comments, refPrefix string) *generator {
+	return &generator{comments: comments, refPrefix: refPrefix, defs: map[string]*schemaObject{}}
+}
+
+// add defines each of the types along with the types they use.
+func (g *generator) add(types ...reflect.Type) error {
+	for _, t := range types {
+		g.schema(t)
+	}
+	return errtrace.Wrap(g.err)
+}
+
+// schema returns a new schema for t, which may reference the definitions of the types it uses.
+func (g *generator) schema(t reflect.Type) *schemaObject {
+	if t.Kind() == reflect.Pointer {
+		return g.schema(t.Elem())
+	}
+	if e, ok := enums[t]; ok {
+		return g.ref(t, func() *schemaObject {
+			description := strings.TrimSpace(g.comments[typeName(t)] + "\n\n" + strings.Join(e.descriptions, "\n"))
+			return &schemaObject{Type: jsonType(t), Description: description, Enum: e.values}
+		})
+	}
+	switch {
+	case t == dateType:
+		return g.ref(t, func() *schemaObject {
+			return &schemaObject{Type: "string", Pattern: "^[0-9]{8}$", Description: "Date in CCYYMMDD format"}
+		})
+	case t == decimalType:
+		return g.ref(t, func() *schemaObject {
+			return &schemaObject{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?$`, Description: "Exact decimal number encoded as a string"}
+		})
+	case t == jsontextValueType:
+		return &schemaObject{}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.ref(t, func() *schemaObject {
+			s := &schemaObject{Type: "object"}
+			g.addProperties(s, t)
+			return s
+		})
+	case reflect.Slice, reflect.Array:
+		return &schemaObject{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &schemaObject{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	}
+	if typ := jsonType(t); typ != "" {
+		return &schemaObject{Type: typ}
+	}
+	if g.err == nil {
+		g.err = errtrace.Errorf("unsupported type %s", t)
+	}
+	return &schemaObject{}
+}
+
+// ref defines t using build if it is not already defined and returns a reference to the definition.
+func (g *generator) ref(t reflect.Type, build func() *schemaObject) *schemaObject {
+	name := defName(t)
+	if _, ok := g.defs[name]; !ok {
+		g.defs[name] = nil // types may refer to themselves
+		s := build()
+		if s.Description == "" {
+			s.Description = g.comments[typeName(t)]
+		}
+		g.defs[name] = s
+	}
+	return &schemaObject{Ref: g.refPrefix + name}
+}
+
+// addProperties adds a property for each field of t which is encoded, including the fields of inlined structs.
+func (g *generator) addProperties(s *schemaObject, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if strings.HasPrefix(t.Name(), "ErrorAndResult[") {
+			var ok bool
+			if tag, ok = errorAndResultTags[f.Name]; !ok && g.err == nil {
+				g.err = errtrace.Errorf("field %s of %s is missing from errorAndResultTags", f.Name, t)
+			}
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		switch {
+		case hasOption(options, "unknown"):
+			s.AdditionalProperties = &schemaObject{}
+			continue
+		case hasOption(options, "inline") || (f.Anonymous && name == ""):
+			inline := f.Type
+			if inline.Kind() == reflect.Pointer {
+				inline = inline.Elem()
+			}
+			g.addProperties(s, inline)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		p := g.schema(f.Type)
+		p.Description = g.comments[typeName(t)+"."+f.Name]
+		s.Properties = append(s.Properties, property{name: name, schema: p})
+	}
+}
+
+func hasOption(options, option string) bool {
+	return slices.Contains(strings.Split(options, ","), option)
+}
+
+// jsonType returns the JSON type of values of t, or an empty string if t does not have a simple JSON type.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return ""
+}
+
+// typeName returns the name of t without its type arguments, which is how it is named in the source.
+func typeName(t reflect.Type) string {
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return name
+}
+
+// defName returns the name of the definition of t. The names of the type arguments of generic types are appended
+// to the type name, so Response[mph.Pricing] is defined as ResponsePricing.
+func defName(t reflect.Type) string {
+	name, args, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return name
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		name += arg[strings.LastIndexByte(arg, '.')+1:]
+	}
+	return name
+}
@@ -0,0 +1,202 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+const apiTitle = "My Price Health API"
+
+// endpoint is an operation of the API.
+type endpoint struct {
+	path   string       // path of the endpoint, which is always called with POST
+	method string       // name of the Client method which calls the endpoint
+	config bool         // whether the endpoint is configured by the PriceConfig headers
+	batch  bool         // whether the request body is an array of inputs
+	input  reflect.Type // type of the inputs
+	output reflect.Type // type of the response
+}
+
+var endpoints = []endpoint{
+	{"/v1/medicare/price/claim", "Price", true, false, reflect.TypeFor[mph.Claim](), reflect.TypeFor[mph.Response[mph.Pricing]]()},
+	{"/v1/medicare/price/claims", "PriceBatch", true, true, reflect.TypeFor[mph.Claim](), reflect.TypeFor[mph.ErrorAndResultResponses[mph.Pricing]]()},
+	{"/v1/medicare/estimate/claims", "EstimateClaims", false, true, reflect.TypeFor[mph.Claim](), reflect.TypeFor[mph.ErrorAndResultResponses[mph.Pricing]]()},
+	{"/v1/medicare/estimate/rate-sheet", "EstimateRateSheet", false, true, reflect.TypeFor[mph.RateSheet](), reflect.TypeFor[mph.ErrorAndResultResponses[mph.Pricing]]()},
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       info                  `json:"info"`
+	Servers    []server              `json:"servers"`
+	Security   []map[string][]string `json:"security"`
+	Paths      map[string]pathItem   `json:"paths"`
+	Components components            `json:"components"`
+}
+
+type info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type server struct {
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+type pathItem struct {
+	Post operation `json:"post"`
+}
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Description string              `json:"description,omitzero"`
+	Parameters  []parameter         `json:"parameters,omitzero"`
+	RequestBody body                `json:"requestBody"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Ref         string        `json:"$ref,omitzero"`
+	Name        string        `json:"name,omitzero"`
+	In          string        `json:"in,omitzero"`
+	Description string        `json:"description,omitzero"`
+	Schema      *schemaObject `json:"schema,omitzero"`
+}
+
+type body struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schemaObject `json:"schema"`
+}
+
+type components struct {
+	Schemas         map[string]*schemaObject  `json:"schemas"`
+	Parameters      map[string]parameter      `json:"parameters"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+// newOpenAPI returns an OpenAPI document describing the endpoints. The schemas of the request and response bodies
+// are the same as in the JSON Schema document.
+func newOpenAPI(comments comments) (openAPIDocument, error) {
+	g := newGenerator(comments, "#/components/schemas/")
+	if err := g.add(rootTypes...); err != nil {
+		return openAPIDocument{}, errtrace.Wrap(err)
+	}
+	headers, err := configHeaders(g)
+	if err != nil {
+		return openAPIDocument{}, errtrace.Wrap(err)
+	}
+	doc := openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    info{Title: apiTitle, Version: "1"},
+		Servers: []server{
+			{URL: "https://api.myprice.health", Description: "Production"},
+			{URL: "https://api-test.myprice.health", Description: "Test"},
+		},
+		Security: []map[string][]string{{"apiKey": {}}},
+		Paths:    map[string]pathItem{},
+		Components: components{
+			Schemas:         g.defs,
+			Parameters:      map[string]parameter{},
+			SecuritySchemes: map[string]securityScheme{"apiKey": {Type: "apiKey", In: "header", Name: "x-api-key"}},
+		},
+	}
+	for _, h := range headers {
+		doc.Components.Parameters[h.Name] = h
+	}
+	for _, e := range endpoints {
+		input := g.schema(e.input)
+		if e.batch {
+			input = &schemaObject{Type: "array", Items: input}
+		}
+		op := operation{
+			OperationID: e.method,
+			Description: comments["Client."+e.method],
+			RequestBody: body{Required: true, Content: map[string]mediaType{"application/json": {Schema: input}}},
+			Responses:   responses(g, e),
+		}
+		if e.config {
+			for _, h := range headers {
+				op.Parameters = append(op.Parameters, parameter{Ref: "#/components/parameters/" + h.Name})
+			}
+		}
+		doc.Paths[e.path] = pathItem{Post: op}
+	}
+	return doc, nil
+}
+
+// responses returns the responses of an endpoint by status. Errors which fail the whole request are problem
+// documents. Errors returned along with partial results are response envelopes with the status of the error.
+func responses(g *generator, e endpoint) map[string]response {
+	envelope := mediaType{Schema: g.schema(e.output)}
+	problem := mediaType{Schema: g.schema(reflect.TypeFor[mph.ResponseError]())}
+	results, partial := "The pricing result", "the claim status showing the step the claim processing reached"
+	if e.batch {
+		results, partial = "The pricing results. Claims which could not be priced have an error in their result", "the results of the claims"
+	}
+	failed := func(description string) response {
+		return response{
+			Description: description + ". Errors which fail the whole request are problem documents (" + mph.ProblemContentType +
+				"). Errors returned with partial results are response envelopes (application/json) holding the error and " + partial + ".",
+			Content: map[string]mediaType{mph.ProblemContentType: problem, "application/json": envelope},
+		}
+	}
+	return map[string]response{
+		"200":     {Description: results + ".", Content: map[string]mediaType{"application/json": envelope}},
+		"400":     failed("The request is invalid, such as a body which could not be decoded or a header with an invalid value"),
+		"413":     {Description: "The request body is too large. The error is a problem document.", Content: map[string]mediaType{mph.ProblemContentType: problem}},
+		"500":     failed("The server failed to process the request"),
+		"default": failed("The request could not be processed"),
+	}
+}
+
+// configHeaders returns a header parameter for each field of PriceConfig. The header names are found by setting
+// each field in turn and checking which header GetHeaders adds for it.
+func configHeaders(g *generator) ([]parameter, error) {
+	t := reflect.TypeFor[mph.PriceConfig]()
+	var headers []parameter
+	for i := range t.NumField() {
+		var config mph.PriceConfig
+		f := reflect.ValueOf(&config).Elem().Field(i)
+		switch f.Kind() {
+		case reflect.Bool:
+			f.SetBool(true)
+		case reflect.Float64:
+			f.SetFloat(1)
+		case reflect.String:
+			f.SetString("set")
+		default:
+			return nil, errtrace.Errorf("unsupported type %s of PriceConfig.%s", f.Type(), t.Field(i).Name)
+		}
+		set := mph.GetHeaders(config)
+		if len(set) != 1 {
+			return nil, errtrace.Errorf("PriceConfig.%s is sent with %d headers", t.Field(i).Name, len(set))
+		}
+		for name := range set {
+			headers = append(headers, parameter{
+				Name:        strings.ToLower(name),
+				In:          "header",
+				Description: g.comments["PriceConfig."+t.Field(i).Name],
+				Schema:      &schemaObject{Type: jsonType(f.Type())},
+			})
+		}
+	}
+	return headers, nil
+}
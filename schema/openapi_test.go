@@ -0,0 +1,99 @@
+package schema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testOperation struct {
+	OperationID string           `json:"operationId"`
+	Parameters  []map[string]any `json:"parameters"`
+	RequestBody struct {
+		Content map[string]struct {
+			Schema map[string]any `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema map[string]any `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+	var doc struct {
+		OpenAPI    string                              `json:"openapi"`
+		Paths      map[string]map[string]testOperation `json:"paths"`
+		Components struct {
+			Schemas    map[string]any            `json:"schemas"`
+			Parameters map[string]map[string]any `json:"parameters"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(OpenAPI, &doc))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.Len(t, doc.Paths, 4)
+	assert.Contains(t, doc.Components.Schemas, "Claim")
+
+	price := doc.Paths["/v1/medicare/price/claim"]["post"]
+	assert.Equal(t, "Price", price.OperationID)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/Claim"}, price.RequestBody.Content["application/json"].Schema)
+	assert.Contains(t, price.Parameters, map[string]any{"$ref": "#/components/parameters/is-commercial"})
+
+	// request errors are problem documents, errors with partial results are envelopes
+	problem := map[string]any{"$ref": "#/components/schemas/ResponseError"}
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/ResponsePricing"}, price.Responses["200"].Content["application/json"].Schema)
+	for _, status := range []string{"400", "500", "default"} {
+		assert.Equal(t, problem, price.Responses[status].Content[mph.ProblemContentType].Schema, status)
+		assert.Equal(t, map[string]any{"$ref": "#/components/schemas/ResponsePricing"}, price.Responses[status].Content["application/json"].Schema, status)
+	}
+	assert.Equal(t, problem, price.Responses["413"].Content[mph.ProblemContentType].Schema)
+	assert.NotContains(t, price.Responses["413"].Content, "application/json")
+	batch := doc.Paths["/v1/medicare/price/claims"]["post"]
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/ErrorAndResultResponsesPricing"}, batch.Responses["400"].Content["application/json"].Schema)
+
+	rateSheet := doc.Paths["/v1/medicare/estimate/rate-sheet"]["post"]
+	assert.Empty(t, rateSheet.Parameters)
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/RateSheet"}}, rateSheet.RequestBody.Content["application/json"].Schema)
+
+	threshold := doc.Components.Parameters["override-threshold"]
+	assert.Equal(t, "header", threshold["in"])
+	assert.Equal(t, map[string]any{"type": "number"}, threshold["schema"])
+	assert.Len(t, doc.Components.Parameters, 15)
+
+	// every documented endpoint is served by the handler
+	handler := mph.NewHandler(nil)
+	for path := range doc.Paths {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader("not json")))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		assert.Contains(t, doc.Paths[path]["post"].Responses["400"].Content, w.Header().Get("Content-Type"), path)
+	}
+}
+
+func TestConfigHeaders(t *testing.T) {
+	t.Parallel()
+	headers, err := configHeaders(newGenerator(comments{"PriceConfig.IsCommercial": "crosswalk commercial codes"}, ""))
+	require.NoError(t, err)
+	for _, h := range headers {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set(h.Name, "true")
+		switch h.Schema.Type {
+		case "string":
+			r.Header.Set(h.Name, "acme")
+		case "number":
+			r.Header.Set(h.Name, "1.5")
+		}
+		config, err := mph.ParseHeaders(r)
+		require.NoError(t, err, h.Name)
+		assert.NotZero(t, config, "%s is parsed by ParseHeaders", h.Name)
+	}
+	assert.Contains(t, headers, parameter{Name: "is-commercial", In: "header", Description: "crosswalk commercial codes", Schema: &schemaObject{Type: "boolean"}})
+}
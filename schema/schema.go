@@ -0,0 +1,160 @@
+// Package schema describes the My Price Health API with JSON Schema and OpenAPI 3.1 so that partners can validate
+// requests and generate clients without reading the Go source.
+//
+// The schemas are generated from the types of the mph package. Properties are named by their JSON tags and
+// described by their field comments. The generated documents are committed and embedded. Regenerate them with
+// go generate after changing the API types.
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/go-json-experiment/json/jsontext"
+)
+
+//go:generate go test -run ^TestGenerate$ -update
+
+var (
+	//go:embed mph.schema.json
+	JSONSchema []byte // JSON Schema (draft 2020-12) with a definition for every type of the API
+
+	//go:embed openapi.json
+	OpenAPI []byte // OpenAPI 3.1 document describing the pricing and estimate endpoints
+)
+
+// Generate generates the JSON Schema and OpenAPI documents. src must contain the Go source of the mph package, which
+// is where the descriptions of types and fields come from.
+func Generate(src fs.FS) (jsonSchema, openAPI []byte, err error) {
+	comments, err := parseComments(src)
+	if err != nil {
+		return nil, nil, errtrace.Wrap(err)
+	}
+	g := newGenerator(comments, "#/$defs/")
+	if err := g.add(rootTypes...); err != nil {
+		return nil, nil, errtrace.Wrap(err)
+	}
+	jsonSchema, err = encode(document{Schema: "https://json-schema.org/draft/2020-12/schema", Title: apiTitle, Defs: g.defs})
+	if err != nil {
+		return nil, nil, errtrace.Wrap(err)
+	}
+	api, err := newOpenAPI(comments)
+	if err != nil {
+		return nil, nil, errtrace.Wrap(err)
+	}
+	openAPI, err = encode(api)
+	if err != nil {
+		return nil, nil, errtrace.Wrap(err)
+	}
+	return jsonSchema, openAPI, nil
+}
+
+// document is a JSON Schema document holding the definitions of the API types.
+type document struct {
+	Schema string                   `json:"$schema"`
+	Title  string                   `json:"title"`
+	Defs   map[string]*schemaObject `json:"$defs"`
+}
+
+// encode encodes v as indented JSON with sorted object members so that the output only changes with the types.
+func encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.MarshalEncode(jsontext.NewEncoder(&buf, jsontext.WithIndent("  ")), v, json.Deterministic(true)); err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// comments holds the doc comments of the types, fields and methods of a package. Types are keyed by their name,
+// fields and methods by the name of their type and their name separated by a dot.
+type comments map[string]string
+
+// parseComments reads the doc comments of the Go files in src, ignoring tests. Trailing comments are used when a
+// declaration has no doc comment, which is how most fields are documented.
+func parseComments(src fs.FS) (comments, error) {
+	names, err := fs.Glob(src, "*.go")
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	c := comments{}
+	fset := token.NewFileSet()
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		data, err := fs.ReadFile(src, name)
+		if err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		file, err := parser.ParseFile(fset, name, data, parser.ParseComments)
+		if err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						c.addType(spec, decl)
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv != nil && len(decl.Recv.List) == 1 {
+					c.add(receiverName(decl.Recv.List[0].Type)+"."+decl.Name.Name, decl.Doc, nil)
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+// addType adds the comments of a type and its fields. The doc comment of a declaration with a single type
+// documents that type.
+func (c comments) addType(spec *ast.TypeSpec, decl *ast.GenDecl) {
+	doc := spec.Doc
+	if doc == nil && len(decl.Specs) == 1 {
+		doc = decl.Doc
+	}
+	c.add(spec.Name.Name, doc, spec.Comment)
+	if s, ok := spec.Type.(*ast.StructType); ok {
+		for _, field := range s.Fields.List {
+			for _, name := range field.Names {
+				c.add(spec.Name.Name+"."+name.Name, field.Doc, field.Comment)
+			}
+			if len(field.Names) == 0 {
+				c.add(spec.Name.Name+"."+receiverName(field.Type), field.Doc, field.Comment)
+			}
+		}
+	}
+}
+
+func (c comments) add(key string, doc, comment *ast.CommentGroup) {
+	if doc == nil {
+		doc = comment
+	}
+	if text := strings.Join(strings.Fields(doc.Text()), " "); text != "" {
+		c[key] = text
+	}
+}
+
+// receiverName returns the name of the type of a method receiver or embedded field.
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/go-json-experiment/json"
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the committed schema documents")
+
+// TestGenerate fails when the committed documents are stale. Run go generate ./schema to update them.
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	jsonSchema, openAPI, err := Generate(os.DirFS("../mph"))
+	require.NoError(t, err)
+	if *update {
+		require.NoError(t, os.WriteFile("mph.schema.json", jsonSchema, 0o644))
+		require.NoError(t, os.WriteFile("openapi.json", openAPI, 0o644))
+		return
+	}
+	assert.Equal(t, string(jsonSchema), string(JSONSchema), "mph.schema.json is stale, run go generate ./schema")
+	assert.Equal(t, string(openAPI), string(OpenAPI), "openapi.json is stale, run go generate ./schema")
+}
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+	var doc struct {
+		Defs map[string]map[string]any `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(JSONSchema, &doc))
+
+	claim := doc.Defs["Claim"]["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "description": "Unique identifier for the claim (from REF D9)"}, claim["claimID"])
+	assert.Contains(t, claim, "npi", "fields of the embedded Provider are inlined")
+	assert.Equal(t, map[string]any{"$ref": "#/$defs/Date", "description": "Earliest service date among services, or statement date if not found"}, claim["dateFrom"])
+	assert.Equal(t, "^[0-9]{8}$", doc.Defs["Date"]["pattern"])
+	assert.Equal(t, "string", doc.Defs["Decimal"]["type"])
+
+	assert.Contains(t, doc.Defs["ClaimRepricingCode"]["enum"], "MED")
+	assert.Contains(t, doc.Defs["LineRepricingCode"]["enum"], "NAM")
+	assert.Contains(t, doc.Defs["MedicareSource"]["enum"], "MPFS")
+	assert.Contains(t, doc.Defs["LineRepricingCode"]["description"], "- `NAM`: Not allowed by Medicare")
+	assert.Equal(t, []any{float64(0), float64(1), float64(2)}, doc.Defs["SexType"]["enum"])
+	assert.Contains(t, doc.Defs["SexType"]["description"], "Biological sex of the patient for clinical purposes")
+	assert.Contains(t, doc.Defs["FormType"]["enum"], "UB-04")
+	assert.Equal(t, "PriceConfig is used to configure the behavior of the pricing API.", doc.Defs["PriceConfig"]["description"])
+	assert.Equal(t, map[string]any{}, doc.Defs["ResponseError"]["additionalProperties"], "problem documents may have extension members")
+
+	result := doc.Defs["ErrorAndResultPricing"]["properties"].(map[string]any)
+	assert.Contains(t, result, "error")
+	assert.Contains(t, result, "claimStatus")
+	assert.Contains(t, result, "medicareAmount", "the result is inlined")
+	responses := doc.Defs["ErrorAndResultResponsesPricing"]["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/$defs/ErrorAndResultPricing"}, responses["results"].(map[string]any)["items"])
+	assert.Contains(t, doc.Defs, "ResponsePricing")
+	assert.Contains(t, doc.Defs, "RateSheet")
+}
+
+func TestErrorAndResultTags(t *testing.T) {
+	t.Parallel()
+	var doc struct {
+		Defs map[string]struct {
+			Properties map[string]any `json:"properties"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(JSONSchema, &doc))
+
+	// every member of an encoded ErrorAndResult is described by the schema
+	result := mph.ErrorAndResult[mph.Pricing]{
+		Error:       &mph.ResponseError{Title: "edit failed"},
+		Result:      mph.Pricing{ClaimID: "1", MedicareAmount: 100},
+		ClaimStatus: mph.ClaimStatus{Step: "priced"},
+	}
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var members map[string]any
+	require.NoError(t, json.Unmarshal(data, &members))
+	require.Len(t, members, 4)
+	for name := range members {
+		assert.Contains(t, doc.Defs["ErrorAndResultPricing"].Properties, name)
+	}
+
+	type ErrorAndResult[Result any] struct {
+		Result Result
+		Extra  int
+	}
+	g := newGenerator(comments{}, "")
+	assert.ErrorContains(t, g.add(reflect.TypeFor[ErrorAndResult[mph.PriceConfig]]()), "field Extra of schema.ErrorAndResult[github.com/mypricehealth/mphgo/mph.PriceConfig] is missing from errorAndResultTags")
+}
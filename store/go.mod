@@ -0,0 +1,32 @@
+module github.com/mypricehealth/mphgo/store
+
+go 1.24.0
+
+require (
+	braces.dev/errtrace v0.3.0
+	github.com/go-json-experiment/json v0.0.0-20250813024750-ebf49471dced
+	github.com/mypricehealth/mphgo v0.0.0
+	github.com/stretchr/testify v1.8.4
+	modernc.org/sqlite v1.40.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/go-querystring v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mypricehealth/decimal v0.0.0-20251224200559-3bbe887e937f // indirect
+	github.com/mypricehealth/sling v1.5.0 // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	modernc.org/libc v1.66.10 // indirect
+	modernc.org/mathutil v1.7.1 // indirect
+	modernc.org/memory v1.11.0 // indirect
+)
+
+replace github.com/mypricehealth/mphgo => ../
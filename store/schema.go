@@ -0,0 +1,182 @@
+package store
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"braces.dev/errtrace"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// column is a database column. Columns with an index store a field of a struct, which may be within structs with
+// an inline db tag. Other columns are keys which are set by the repository.
+type column struct {
+	name    string
+	sqlType string
+	index   []int
+}
+
+// table is a database table and the columns and indexes it needs.
+type table struct {
+	name       string
+	primaryKey string
+	indexes    []string
+	columns    []column
+}
+
+var (
+	dateType   = reflect.TypeFor[mph.Date]()
+	valuerType = reflect.TypeFor[driver.Valuer]()
+)
+
+var (
+	claimsTable = table{
+		name:       "claims",
+		primaryKey: "fingerprint",
+		indexes:    []string{"claim_id", "npi", "date_from"},
+		columns: []column{
+			{name: "fingerprint", sqlType: "TEXT NOT NULL"},
+			{name: "claim_id", sqlType: "TEXT NOT NULL DEFAULT ''"},
+			{name: "npi", sqlType: "TEXT NOT NULL DEFAULT ''"},
+			{name: "date_from", sqlType: "DATE"},
+			{name: "date_through", sqlType: "DATE"},
+			{name: "saved_at", sqlType: "TIMESTAMP NOT NULL"},
+			{name: "claim", sqlType: "TEXT NOT NULL"},
+		},
+	}
+	pricingTable = table{
+		name:       "pricing",
+		primaryKey: "id",
+		indexes:    []string{"claim_id", "fingerprint"},
+		columns: append([]column{
+			{name: "id", sqlType: "TEXT NOT NULL"},
+			{name: "claim_id", sqlType: "TEXT NOT NULL DEFAULT ''"},
+			{name: "fingerprint", sqlType: "TEXT NOT NULL"},
+			{name: "priced_at", sqlType: "TIMESTAMP NOT NULL"},
+		}, fieldColumns(reflect.TypeFor[mph.Pricing](), "services")...),
+	}
+	pricedServicesTable = table{
+		name:       "priced_services",
+		primaryKey: "pricing_id, line_index",
+		columns: append([]column{
+			{name: "pricing_id", sqlType: "TEXT NOT NULL"},
+			{name: "line_index", sqlType: "BIGINT NOT NULL"},
+			{name: "line_number", sqlType: "TEXT NOT NULL DEFAULT ''"},
+		}, fieldColumns(reflect.TypeFor[mph.PricedService]())...),
+	}
+	tables = []table{claimsTable, pricingTable, pricedServicesTable}
+)
+
+// Schema returns the statements which create the tables and indexes used by SQLRepository. The column names are
+// the db tags of mph.Pricing and mph.PricedService. The statements use IF NOT EXISTS so they can be run against an
+// existing database, but they do not add columns to existing tables (see SQLRepository.Migrate).
+func Schema() []string {
+	var statements []string
+	for _, t := range tables {
+		statements = append(statements, t.create()...)
+	}
+	return statements
+}
+
+// create returns the statements which create the table and its indexes.
+func (t table) create() []string {
+	definitions := make([]string, 0, len(t.columns)+1)
+	for _, c := range t.columns {
+		definitions = append(definitions, c.name+" "+c.sqlType)
+	}
+	definitions = append(definitions, "PRIMARY KEY ("+t.primaryKey+")")
+	statements := []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", t.name, strings.Join(definitions, ",\n\t"))}
+	for _, name := range t.indexes {
+		statements = append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s ON %s (%s)", t.name, name, t.name, name))
+	}
+	return statements
+}
+
+// addColumns returns the statements which add the columns of the table which are not in existing.
+func (t table) addColumns(existing []string) []string {
+	var statements []string
+	for _, c := range t.columns {
+		if !slices.ContainsFunc(existing, func(name string) bool { return strings.EqualFold(name, c.name) }) {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", t.name, c.name, c.sqlType))
+		}
+	}
+	return statements
+}
+
+// fields returns the columns which store struct fields.
+func (t table) fields() []column {
+	var fields []column
+	for _, c := range t.columns {
+		if c.index != nil {
+			fields = append(fields, c)
+		}
+	}
+	return fields
+}
+
+// fieldColumns returns the columns of the fields of t, leaving out fields of unsupported types. The tests check that
+// every field of the stored types is supported.
+func fieldColumns(t reflect.Type, skip ...string) []column {
+	columns, _ := structColumns(t, nil, skip)
+	return columns
+}
+
+// structColumns returns a column for each field of t with a db tag. Fields with an inline db tag are flattened
+// into the columns of their struct. Fields of unsupported types are left out and reported in the error.
+func structColumns(t reflect.Type, index []int, skip []string) ([]column, error) {
+	var columns []column
+	var errs []error
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, options, _ := strings.Cut(f.Tag.Get("db"), ",")
+		fieldIndex := append(append([]int(nil), index...), i)
+		if options == "inline" {
+			inline := f.Type
+			if inline.Kind() == reflect.Pointer {
+				inline = inline.Elem()
+			}
+			inlineColumns, err := structColumns(inline, fieldIndex, skip)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			columns = append(columns, inlineColumns...)
+			continue
+		}
+		if name == "" || name == "-" || !f.IsExported() || slices.Contains(skip, name) {
+			continue
+		}
+		sqlType, err := columnType(f.Type)
+		if err != nil {
+			errs = append(errs, errtrace.Errorf("column %s of %s: %w", name, t, err))
+			continue
+		}
+		columns = append(columns, column{name: name, sqlType: sqlType, index: fieldIndex})
+	}
+	return columns, errtrace.Wrap(errors.Join(errs...))
+}
+
+// columnType returns the SQL type of a column storing t. Types which implement driver.Valuer may store NULL so
+// that they can be added to existing tables. Other columns are NOT NULL with the zero value as the default.
+func columnType(t reflect.Type) (string, error) {
+	switch {
+	case t == dateType:
+		return "DATE", nil
+	case t.Implements(valuerType):
+		return "TEXT", nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "TEXT NOT NULL DEFAULT ''", nil
+	case reflect.Bool:
+		return "BOOLEAN NOT NULL DEFAULT FALSE", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "BIGINT NOT NULL DEFAULT 0", nil
+	case reflect.Float32, reflect.Float64:
+		return "DOUBLE PRECISION NOT NULL DEFAULT 0", nil
+	}
+	return "", errtrace.Errorf("unsupported type %s", t)
+}
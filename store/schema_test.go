@@ -0,0 +1,73 @@
+package store
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+	statements := Schema()
+	require.NotEmpty(t, statements)
+	assert.True(t, strings.HasPrefix(statements[0], "CREATE TABLE IF NOT EXISTS claims (\n\tfingerprint TEXT NOT NULL,"))
+
+	pricing := strings.Join(pricingTable.create(), "\n")
+	for _, column := range []string{
+		"medicare_amount DOUBLE PRECISION NOT NULL DEFAULT 0",
+		"medicare_repricing_code TEXT NOT NULL DEFAULT ''",
+		"inpatient_drg TEXT NOT NULL DEFAULT ''",
+		"provider_mac BIGINT NOT NULL DEFAULT 0",
+		"provider_rural_indicator TEXT",
+		"claim_edit_overall_disposition TEXT NOT NULL DEFAULT ''",
+		"price_config_is_commercial BOOLEAN NOT NULL DEFAULT FALSE",
+		"edit_error TEXT",
+		"PRIMARY KEY (id)",
+	} {
+		assert.Contains(t, pricing, column)
+	}
+	assert.NotContains(t, pricing, "services")
+	assert.Contains(t, pricing, "CREATE INDEX IF NOT EXISTS pricing_claim_id ON pricing (claim_id)")
+
+	services := strings.Join(pricedServicesTable.create(), "\n")
+	assert.Contains(t, services, "allowed_repricing_formula_per_diem DOUBLE PRECISION NOT NULL DEFAULT 0")
+	assert.Contains(t, services, "PRIMARY KEY (pricing_id, line_index)")
+}
+
+func TestAddColumns(t *testing.T) {
+	t.Parallel()
+	var existing []string
+	for _, c := range pricedServicesTable.columns {
+		if c.name != "payment_apc" && c.name != "hcpcs_apc" {
+			existing = append(existing, strings.ToUpper(c.name))
+		}
+	}
+	assert.Equal(t, []string{
+		"ALTER TABLE priced_services ADD COLUMN hcpcs_apc TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE priced_services ADD COLUMN payment_apc TEXT NOT NULL DEFAULT ''",
+	}, pricedServicesTable.addColumns(existing))
+}
+
+func TestStructColumns(t *testing.T) {
+	t.Parallel()
+	for _, typ := range []reflect.Type{reflect.TypeFor[mph.Pricing](), reflect.TypeFor[mph.PricedService]()} {
+		_, err := structColumns(typ, nil, []string{"services"})
+		assert.NoError(t, err, "every field of %s with a db tag must have a supported type", typ)
+	}
+}
+
+func TestStructColumnsUnsupported(t *testing.T) {
+	t.Parallel()
+	type unsupported struct {
+		Name   string `db:"name"`
+		Values []int  `db:"values"`
+	}
+	columns, err := structColumns(reflect.TypeFor[unsupported](), nil, nil)
+	assert.ErrorContains(t, err, "column values of store.unsupported: unsupported type []int")
+	require.Len(t, columns, 1)
+	assert.Equal(t, "name", columns[0].name)
+}
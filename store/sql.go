@@ -0,0 +1,326 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"braces.dev/errtrace"
+	"github.com/go-json-experiment/json"
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// SQLRepository is a Repository which stores claims and pricing results using database/sql. Claims are stored as
+// JSON along with the columns used to look them up, pricing results in the pricing table and each priced service in
+// the priced_services table. Statements use numbered ($1) placeholders as supported by PostgreSQL and SQLite.
+type SQLRepository struct {
+	db         *sql.DB
+	now        func() time.Time
+	listFormat mph.ListFormat // format of the columns storing edit reasons
+}
+
+var _ Repository = &SQLRepository{}
+
+// NewSQLRepository creates a repository using db. Call Migrate to create the tables before using it.
+func NewSQLRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db, now: time.Now}
+}
+
+// SetListFormat sets the format used to store edit reasons, which are stored as JSON arrays by default. Use
+// mph.ListFormatArray to store them as PostgreSQL array literals. SetListFormat is not safe to call concurrently with
+// other methods.
+func (r *SQLRepository) SetListFormat(format mph.ListFormat) *SQLRepository {
+	r.listFormat = format
+	return r
+}
+
+// execer is implemented by sql.DB and sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Migrate creates the tables and indexes which do not exist and adds the columns missing from existing tables, such
+// as the columns for fields added to mph.Pricing since the tables were created.
+func (r *SQLRepository) Migrate(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errtrace.Wrap(err)
+	}
+	defer tx.Rollback() //nolint:errcheck // the error is irrelevant once the transaction is committed
+	for _, t := range tables {
+		for _, statement := range t.create() {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return errtrace.Errorf("creating %s: %w", t.name, err)
+			}
+		}
+		existing, err := existingColumns(ctx, tx, t.name)
+		if err != nil {
+			return errtrace.Wrap(err)
+		}
+		for _, statement := range t.addColumns(existing) {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return errtrace.Errorf("migrating %s: %w", t.name, err)
+			}
+		}
+	}
+	return errtrace.Wrap(tx.Commit())
+}
+
+// existingColumns returns the names of the columns of a table.
+func existingColumns(ctx context.Context, tx *sql.Tx, table string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT * FROM "+table+" WHERE 1 = 0")
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	defer rows.Close()
+	return errtrace.Wrap2(rows.Columns())
+}
+
+func (r *SQLRepository) SaveClaim(ctx context.Context, claim mph.Claim) (string, error) {
+	return errtrace.Wrap2(r.saveClaim(ctx, r.db, claim))
+}
+
+func (r *SQLRepository) saveClaim(ctx context.Context, db execer, claim mph.Claim) (string, error) {
+	fingerprint, err := claim.Fingerprint()
+	if err != nil {
+		return "", errtrace.Wrap(err)
+	}
+	data, err := json.Marshal(claim)
+	if err != nil {
+		return "", errtrace.Wrap(err)
+	}
+	_, err = db.ExecContext(ctx, insert(claimsTable)+" ON CONFLICT (fingerprint) DO NOTHING",
+		fingerprint, claim.ClaimID, claim.NPI, claim.DateFrom, claim.DateThrough, r.now().UTC(), string(data))
+	if err != nil {
+		return "", errtrace.Errorf("saving claim %q: %w", claim.ClaimID, err)
+	}
+	return fingerprint, nil
+}
+
+func (r *SQLRepository) SavePricing(ctx context.Context, claim mph.Claim, pricing mph.Pricing) (Record, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Record{}, errtrace.Wrap(err)
+	}
+	defer tx.Rollback() //nolint:errcheck // the error is irrelevant once the transaction is committed
+
+	fingerprint, err := r.saveClaim(ctx, tx, claim)
+	if err != nil {
+		return Record{}, errtrace.Wrap(err)
+	}
+	record := Record{ID: newID(), ClaimID: claim.ClaimID, Fingerprint: fingerprint, PricedAt: r.now().UTC(), Pricing: pricing}
+	args := append([]any{record.ID, record.ClaimID, record.Fingerprint, record.PricedAt}, r.fieldValues(pricingTable, pricing)...)
+	if _, err := tx.ExecContext(ctx, insert(pricingTable), args...); err != nil {
+		return Record{}, errtrace.Errorf("saving pricing of claim %q: %w", claim.ClaimID, err)
+	}
+	for i, service := range pricing.Services {
+		args := append([]any{record.ID, i, service.LineNumber}, r.fieldValues(pricedServicesTable, service)...)
+		if _, err := tx.ExecContext(ctx, insert(pricedServicesTable), args...); err != nil {
+			return Record{}, errtrace.Errorf("saving pricing of line %q of claim %q: %w", service.LineNumber, claim.ClaimID, err)
+		}
+	}
+	return record, errtrace.Wrap(tx.Commit())
+}
+
+func (r *SQLRepository) Claims(ctx context.Context, query Query) ([]mph.Claim, error) {
+	where, args := query.where()
+	rows, err := r.db.QueryContext(ctx, "SELECT c.claim FROM claims c"+where+" ORDER BY c.saved_at, c.fingerprint", args...)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	defer rows.Close()
+
+	var claims []mph.Claim
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		var claim mph.Claim
+		if err := mph.Unmarshal([]byte(data), &claim); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		claims = append(claims, claim)
+	}
+	return claims, errtrace.Wrap(rows.Err())
+}
+
+func (r *SQLRepository) Pricing(ctx context.Context, query Query) ([]Record, error) {
+	where, args := query.where()
+	rows, err := r.db.QueryContext(ctx, "SELECT "+selectColumns("p", pricingTable)+" FROM pricing p JOIN claims c ON c.fingerprint = p.fingerprint"+where+" ORDER BY p.priced_at, p.id", args...)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		dest := append([]any{&record.ID, &record.ClaimID, &record.Fingerprint, &record.PricedAt}, fieldAddrs(pricingTable, &record.Pricing)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		clearEmptyInline(reflect.ValueOf(&record.Pricing).Elem())
+		record.PricedAt = record.PricedAt.UTC()
+		record.Pricing.ClaimID = record.ClaimID
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	rows.Close()
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	services, err := r.pricedServices(ctx, where, args)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	for i := range records {
+		records[i].Pricing.Services = services[records[i].ID]
+	}
+	return records, nil
+}
+
+// pricedServices returns the priced services saved with the pricing records of the claims matching the WHERE clause,
+// keyed by the ID of their pricing record. The services of every record are read with a single query.
+func (r *SQLRepository) pricedServices(ctx context.Context, where string, args []any) (map[string]mph.PricedServices, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+selectColumns("s", pricedServicesTable)+" FROM priced_services s JOIN pricing p ON p.id = s.pricing_id JOIN claims c ON c.fingerprint = p.fingerprint"+where+" ORDER BY s.pricing_id, s.line_index", args...)
+	if err != nil {
+		return nil, errtrace.Wrap(err)
+	}
+	defer rows.Close()
+
+	services := map[string]mph.PricedServices{}
+	for rows.Next() {
+		var service mph.PricedService
+		var id string
+		var index int
+		dest := append([]any{&id, &index, &service.LineNumber}, fieldAddrs(pricedServicesTable, &service)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, errtrace.Wrap(err)
+		}
+		clearEmptyInline(reflect.ValueOf(&service).Elem())
+		services[id] = append(services[id], service)
+	}
+	return services, errtrace.Wrap(rows.Err())
+}
+
+// where returns the WHERE clause selecting claims (aliased c) which match the query, and its arguments.
+func (q Query) where() (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, values ...any) {
+		for _, v := range values {
+			args = append(args, v)
+			condition = strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1)
+		}
+		conditions = append(conditions, condition)
+	}
+	if q.ClaimID != "" {
+		add("c.claim_id = ?", q.ClaimID)
+	}
+	if q.Fingerprint != "" {
+		add("c.fingerprint = ?", q.Fingerprint)
+	}
+	if q.NPI != "" {
+		add("c.npi = ?", q.NPI)
+	}
+	if !q.Dates.IsZero() {
+		through := q.Dates.Through
+		if through.IsZero() {
+			through = q.Dates.From
+		}
+		add("c.date_from <= ? AND COALESCE(c.date_through, c.date_from) >= ?", through, q.Dates.From)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// insert returns an INSERT statement for every column of the table.
+func insert(t table) string {
+	names := make([]string, len(t.columns))
+	placeholders := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = c.name
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+	}
+	return "INSERT INTO " + t.name + " (" + strings.Join(names, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+}
+
+// selectColumns returns every column of the table prefixed by alias.
+func selectColumns(alias string, t table) string {
+	names := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = alias + "." + c.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// fieldValues returns the value of each field column of the table from v. Fields within nil inline structs are
+// stored as their zero value and edit reasons are stored in the list format of the repository.
+func (r *SQLRepository) fieldValues(t table, v any) []any {
+	rv := reflect.ValueOf(v)
+	var values []any
+	for _, c := range t.fields() {
+		field, err := rv.FieldByIndexErr(c.index)
+		if err != nil {
+			field = reflect.Zero(rv.Type().FieldByIndex(c.index).Type)
+		}
+		value := field.Interface()
+		if reasons, ok := value.(mph.EditReasons); ok {
+			value = mph.SQLList{List: reasons, Format: r.listFormat}
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// fieldAddrs returns a pointer to each field of the table within the struct pointed to by v, allocating inline
+// structs as needed.
+func fieldAddrs(t table, v any) []any {
+	rv := reflect.ValueOf(v).Elem()
+	var addrs []any
+	for _, c := range t.fields() {
+		field := rv
+		for _, i := range c.index {
+			if field.Kind() == reflect.Pointer {
+				if field.IsNil() {
+					field.Set(reflect.New(field.Type().Elem()))
+				}
+				field = field.Elem()
+			}
+			field = field.Field(i)
+		}
+		addrs = append(addrs, field.Addr().Interface())
+	}
+	return addrs
+}
+
+// clearEmptyInline sets inline struct pointers which were allocated by fieldAddrs but have no values back to nil.
+func clearEmptyInline(v reflect.Value) {
+	for i := range v.NumField() {
+		f := v.Field(i)
+		if f.Kind() == reflect.Pointer && strings.HasSuffix(v.Type().Field(i).Tag.Get("db"), ",inline") && !f.IsNil() {
+			clearEmptyInline(f.Elem())
+			if f.Elem().IsZero() {
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+	}
+}
+
+// newID returns a random identifier for a record.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// newTestRepository returns a migrated repository backed by a new SQLite database.
+func newTestRepository(t *testing.T) *SQLRepository {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	r := NewSQLRepository(db)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	require.NoError(t, r.Migrate(context.Background()))
+	return r
+}
+
+func testClaim(id, npi string, from, through mph.Date) mph.Claim {
+	return mph.Claim{
+		ClaimID:     id,
+		Provider:    mph.Provider{NPI: npi},
+		FormType:    mph.UBFormType,
+		DateFrom:    from,
+		DateThrough: through,
+		Services:    []mph.Service{{LineNumber: "1", ProcedureCode: "99213", BilledAmount: 150}},
+	}
+}
+
+func TestSQLRepositoryPricing(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	claim := testClaim("c1", "1234567893", mph.NewDate(2024, 1, 1), mph.NewDate(2024, 1, 3))
+	pricing := mph.Pricing{
+		ClaimID:               "c1",
+		MedicareAmount:        1000.25,
+		MedicareRepricingCode: mph.ClaimRepricingCode("MED"),
+		InpatientPriceDetail:  mph.InpatientPriceDetail{DRG: "470", DRGAmount: 900},
+		ProviderDetail:        mph.ProviderDetail{CCN: "010001", MAC: 5, RuralIndicator: mph.RuralIndicatorRural},
+		EditDetail:            &mph.ClaimEdits{ClaimOverallDisposition: "pay", ClaimRejectionReasons: mph.EditReasons{"a", "b"}},
+		PriceConfig:           mph.PriceConfig{IsCommercial: true, ContractRuleset: "acme"},
+		EditError:             &mph.ResponseError{Title: "edit failed", Detail: "missing DRG"},
+		Services: mph.PricedServices{
+			{LineNumber: "1", MedicareAmount: 500, AllowedRepricingFormula: mph.AllowedRepricingFormula{MedicarePercent: 150}},
+			{LineNumber: "2", ProviderDetail: &mph.ProviderDetail{CCN: "010002"}, EditDetail: &mph.LineEdits{ProcedureEdits: mph.EditReasons{"edit"}}},
+		},
+	}
+	saved, err := r.SavePricing(ctx, claim, pricing)
+	require.NoError(t, err)
+	fingerprint, err := claim.Fingerprint()
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, saved.Fingerprint)
+	assert.Len(t, saved.ID, 32)
+
+	records, err := r.Pricing(ctx, Query{ClaimID: "c1"})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, saved, records[0])
+
+	// a pricing without details is stored without allocating them
+	_, err = r.SavePricing(ctx, claim, mph.Pricing{ClaimID: "c1", Services: mph.PricedServices{{LineNumber: "1"}}})
+	require.NoError(t, err)
+	records, err = r.Pricing(ctx, Query{Fingerprint: fingerprint})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Nil(t, records[1].Pricing.EditDetail)
+	assert.Nil(t, records[1].Pricing.EditError)
+	assert.Equal(t, mph.PricedServices{{LineNumber: "1"}}, records[1].Pricing.Services)
+
+	// the claim was saved once along with its pricing
+	claims, err := r.Claims(ctx, Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []mph.Claim{claim}, claims)
+}
+
+func TestSQLRepositoryListFormat(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestRepository(t).SetListFormat(mph.ListFormatArray)
+
+	claim := testClaim("c1", "1234567893", mph.NewDate(2024, 1, 1), mph.Date{})
+	pricing := mph.Pricing{
+		ClaimID:    "c1",
+		EditDetail: &mph.ClaimEdits{ClaimRejectionReasons: mph.EditReasons{"a", "b, c"}},
+		Services:   mph.PricedServices{{LineNumber: "1", EditDetail: &mph.LineEdits{ProcedureEdits: mph.EditReasons{"edit"}}}},
+	}
+	saved, err := r.SavePricing(ctx, claim, pricing)
+	require.NoError(t, err)
+
+	var claimReasons, lineReasons string
+	require.NoError(t, r.db.QueryRowContext(ctx, "SELECT claim_edit_rejection_reasons FROM pricing").Scan(&claimReasons))
+	assert.Equal(t, `{"a","b, c"}`, claimReasons)
+	require.NoError(t, r.db.QueryRowContext(ctx, "SELECT procedure_edits FROM priced_services").Scan(&lineReasons))
+	assert.Equal(t, `{"edit"}`, lineReasons)
+
+	records, err := r.Pricing(ctx, Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{saved}, records)
+}
+
+func TestSQLRepositoryQuery(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	stay := testClaim("c1", "1234567893", mph.NewDate(2024, 1, 1), mph.NewDate(2024, 1, 10))
+	visit := testClaim("c2", "1234567893", mph.NewDate(2024, 2, 1), mph.Date{})
+	other := testClaim("c3", "1111111112", mph.NewDate(2024, 1, 5), mph.NewDate(2024, 1, 5))
+	resubmitted := stay
+	resubmitted.BillTypeSequence = mph.ReplacementBillTypeSequence
+	for _, claim := range []mph.Claim{stay, visit, other, resubmitted} {
+		_, err := r.SavePricing(ctx, claim, mph.Pricing{ClaimID: claim.ClaimID})
+		require.NoError(t, err)
+	}
+	fingerprint, err := r.SaveClaim(ctx, other)
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		query    Query
+		expected []mph.Claim
+	}{
+		"all":         {Query{}, []mph.Claim{stay, visit, other, resubmitted}},
+		"claim ID":    {Query{ClaimID: "c1"}, []mph.Claim{stay, resubmitted}},
+		"fingerprint": {Query{Fingerprint: fingerprint}, []mph.Claim{other}},
+		"NPI":         {Query{NPI: "1234567893"}, []mph.Claim{stay, visit, resubmitted}},
+		"overlap":     {Query{Dates: mph.NewDateRange(mph.NewDate(2024, 1, 10), mph.NewDate(2024, 2, 1))}, []mph.Claim{stay, visit, resubmitted}},
+		"single date": {Query{Dates: mph.NewDateRange(mph.NewDate(2024, 1, 5), mph.Date{})}, []mph.Claim{stay, other, resubmitted}},
+		"no overlap":  {Query{Dates: mph.NewDateRange(mph.NewDate(2024, 1, 11), mph.NewDate(2024, 1, 31))}, nil},
+		"combined":    {Query{NPI: "1111111112", Dates: mph.NewDateRange(mph.NewDate(2024, 1, 1), mph.NewDate(2024, 1, 31))}, []mph.Claim{other}},
+	} {
+		claims, err := r.Claims(ctx, test.query)
+		require.NoError(t, err, name)
+		assert.Equal(t, test.expected, claims, name)
+
+		records, err := r.Pricing(ctx, test.query)
+		require.NoError(t, err, name)
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.ClaimID)
+		}
+		var expectedIDs []string
+		for _, claim := range test.expected {
+			expectedIDs = append(expectedIDs, claim.ClaimID)
+		}
+		assert.Equal(t, expectedIDs, ids, name)
+	}
+}
+
+func TestSQLRepositoryMigrate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	_, err := r.db.ExecContext(ctx, "ALTER TABLE pricing DROP COLUMN inpatient_drg")
+	require.NoError(t, err)
+	require.NoError(t, r.Migrate(ctx))
+	require.NoError(t, r.Migrate(ctx))
+
+	claim := testClaim("c1", "1234567893", mph.NewDate(2024, 1, 1), mph.Date{})
+	saved, err := r.SavePricing(ctx, claim, mph.Pricing{ClaimID: "c1", InpatientPriceDetail: mph.InpatientPriceDetail{DRG: "470"}})
+	require.NoError(t, err)
+	records, err := r.Pricing(ctx, Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{saved}, records)
+}
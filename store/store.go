@@ -0,0 +1,35 @@
+// Package store persists claims and their pricing results in a relational database.
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// Repository stores claims and the results of pricing them. Claims are identified by their fingerprint (see
+// mph.Claim.Fingerprint) so that every version of a resubmitted claim is kept.
+type Repository interface {
+	SaveClaim(ctx context.Context, claim mph.Claim) (string, error)                        // SaveClaim saves the claim if it has not already been saved and returns its fingerprint
+	SavePricing(ctx context.Context, claim mph.Claim, pricing mph.Pricing) (Record, error) // SavePricing saves the pricing of claim along with the claim
+	Claims(ctx context.Context, query Query) ([]mph.Claim, error)                          // Claims returns the claims matching query in the order they were saved
+	Pricing(ctx context.Context, query Query) ([]Record, error)                            // Pricing returns the pricing of the claims matching query in the order they were saved
+}
+
+// Query selects claims and pricing results. Fields which are not set are not used to filter.
+type Query struct {
+	ClaimID     string        // Claim ID assigned by the submitter
+	Fingerprint string        // Fingerprint of the exact claim (see mph.Claim.Fingerprint)
+	NPI         string        // NPI of the billing provider
+	Dates       mph.DateRange // Claims with dates of service which overlap this range
+}
+
+// Record is a saved pricing result.
+type Record struct {
+	ID          string      `json:"id"`          // Identifies the record
+	ClaimID     string      `json:"claimID"`     // Claim ID of the claim which was priced
+	Fingerprint string      `json:"fingerprint"` // Fingerprint of the claim which was priced
+	PricedAt    time.Time   `json:"pricedAt"`    // When the record was saved (UTC)
+	Pricing     mph.Pricing `json:"pricing"`     // The pricing result including its services
+}
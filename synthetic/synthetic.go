@@ -0,0 +1,385 @@
+// Package synthetic generates realistic but fake claims from a seed for load tests, property tests and fuzzing.
+package synthetic
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing/quick"
+	"time"
+
+	"github.com/mypricehealth/mphgo/mph"
+)
+
+// Kind is the type of claim to generate.
+type Kind string
+
+const (
+	KindInpatient    Kind = "inpatient"    // UB-04 inpatient claim with a DRG and revenue code lines
+	KindOutpatient   Kind = "outpatient"   // UB-04 outpatient claim with revenue codes and HCPCS codes
+	KindProfessional Kind = "professional" // HCFA professional claim
+	KindAmbulance    Kind = "ambulance"    // HCFA ambulance claim with a base rate, mileage and pickup ZIP
+	KindAnesthesia   Kind = "anesthesia"   // HCFA anesthesia claim with time reported in minutes
+)
+
+// Kinds lists every kind of claim which can be generated.
+var Kinds = []Kind{KindInpatient, KindOutpatient, KindProfessional, KindAmbulance, KindAnesthesia}
+
+// Defect is a deliberate problem added to a generated claim.
+type Defect string
+
+const (
+	DefectNone                 Defect = ""
+	DefectMissingNPI           Defect = "missingNPI"           // the billing provider NPI is removed
+	DefectInvalidNPI           Defect = "invalidNPI"           // the billing provider NPI fails the check digit
+	DefectMissingProcedureCode Defect = "missingProcedureCode" // the first line has no revenue or procedure code
+	DefectDatesReversed        Defect = "datesReversed"        // the claim ends before it starts
+	DefectNegativeAmount       Defect = "negativeAmount"       // the first line has a negative billed amount
+	DefectTotalMismatch        Defect = "totalMismatch"        // the claim billed amount does not equal the sum of its lines
+	DefectMissingDiagnosis     Defect = "missingDiagnosis"     // the claim has no diagnoses
+	DefectNoServices           Defect = "noServices"           // the claim has no service lines
+)
+
+// Defects lists every defect which can be added to a claim.
+var Defects = []Defect{DefectMissingNPI, DefectInvalidNPI, DefectMissingProcedureCode, DefectDatesReversed, DefectNegativeAmount, DefectTotalMismatch, DefectMissingDiagnosis, DefectNoServices}
+
+// Options controls the claims which are generated. Zero values use the defaults.
+type Options struct {
+	Kinds      []Kind   // Kinds of claims to generate, chosen uniformly (default all)
+	MinLines   int      // Minimum service lines for inpatient, outpatient and professional claims (default 1)
+	MaxLines   int      // Maximum service lines for inpatient, outpatient and professional claims (default 8)
+	MeanBilled float64  // Mean billed amount of a professional service line; other kinds are scaled from it (default 250)
+	Sigma      float64  // Spread of the log-normal billed amount distribution (default 0.75)
+	DefectRate float64  // Probability that a claim has a defect, from 0 to 1 (default 0)
+	Defects    []Defect // Defects to choose from (default all)
+	Year       int      // Year of service (default 2024)
+}
+
+// withDefaults returns the options with defaults applied.
+func (o Options) withDefaults() Options {
+	if len(o.Kinds) == 0 {
+		o.Kinds = Kinds
+	}
+	if o.MinLines <= 0 {
+		o.MinLines = 1
+	}
+	if o.MaxLines < o.MinLines {
+		o.MaxLines = max(o.MinLines, 8)
+	}
+	if o.MeanBilled <= 0 {
+		o.MeanBilled = 250
+	}
+	if o.Sigma <= 0 {
+		o.Sigma = 0.75
+	}
+	if len(o.Defects) == 0 {
+		o.Defects = Defects
+	}
+	if o.Year == 0 {
+		o.Year = 2024
+	}
+	return o
+}
+
+// Sample is a generated claim along with how it was generated.
+type Sample struct {
+	Claim  mph.Claim
+	Kind   Kind
+	Defect Defect // DefectNone for valid claims
+}
+
+// Generator generates claims. The same seed and options always produce the same claims. A Generator is not safe
+// for concurrent use.
+type Generator struct {
+	rand *rand.Rand
+	opts Options
+}
+
+// New returns a Generator for seed.
+func New(seed int64, opts Options) *Generator {
+	return newGenerator(rand.New(rand.NewSource(seed)), opts)
+}
+
+func newGenerator(r *rand.Rand, opts Options) *Generator {
+	return &Generator{rand: r, opts: opts.withDefaults()}
+}
+
+// ClaimFromSeed returns a single claim for seed. It is convenient for fuzz tests which take a seed argument.
+func ClaimFromSeed(seed int64, opts Options) mph.Claim {
+	return New(seed, opts).Claim()
+}
+
+// Claim returns the next claim.
+func (g *Generator) Claim() mph.Claim {
+	return g.Next().Claim
+}
+
+// Claims returns the next n claims.
+func (g *Generator) Claims(n int) []mph.Claim {
+	claims := make([]mph.Claim, n)
+	for i := range claims {
+		claims[i] = g.Claim()
+	}
+	return claims
+}
+
+// Next returns the next sample using a kind and defect chosen from the options.
+func (g *Generator) Next() Sample {
+	kind := g.opts.Kinds[g.rand.Intn(len(g.opts.Kinds))]
+	defect := DefectNone
+	if g.rand.Float64() < g.opts.DefectRate {
+		defect = g.opts.Defects[g.rand.Intn(len(g.opts.Defects))]
+	}
+	return g.Generate(kind, defect)
+}
+
+// Generate returns the next claim of kind with defect.
+func (g *Generator) Generate(kind Kind, defect Defect) Sample {
+	var claim mph.Claim
+	switch kind {
+	case KindInpatient:
+		claim = g.inpatient()
+	case KindOutpatient:
+		claim = g.outpatient()
+	case KindAmbulance:
+		claim = g.ambulance()
+	case KindAnesthesia:
+		claim = g.anesthesia()
+	default:
+		kind = KindProfessional
+		claim = g.professional()
+	}
+	for i := range claim.Services {
+		claim.Services[i].LineNumber = strconv.Itoa(i + 1)
+		claim.BilledAmount += claim.Services[i].BilledAmount
+	}
+	claim.BilledAmount = roundCents(claim.BilledAmount)
+	g.addDefect(&claim, defect)
+	return Sample{Claim: claim, Kind: kind, Defect: defect}
+}
+
+// reference data used to build claims
+var (
+	locations = []struct{ zip, state string }{
+		{"35960", "AL"}, {"30303", "GA"}, {"60611", "IL"}, {"75201", "TX"}, {"10016", "NY"},
+		{"94110", "CA"}, {"98104", "WA"}, {"02114", "MA"}, {"80218", "CO"}, {"33136", "FL"},
+	}
+	diagnoses       = []string{"I10", "E119", "J449", "N186", "E785", "I509", "J189", "M545", "R0789", "Z992", "K219", "F329"}
+	drgs            = []string{"470", "291", "871", "392", "190", "683", "194", "065", "603", "378", "461"}
+	inpatientRevs   = []string{"110", "120", "250", "260", "300", "320", "360", "370", "450", "636", "730"}
+	outpatientLines = []struct{ rev, code string }{
+		{"450", "99284"}, {"320", "71046"}, {"300", "80053"}, {"300", "85025"}, {"636", "J1885"},
+		{"510", "G0463"}, {"350", "70450"}, {"730", "93005"}, {"250", ""}, {"360", "29125"},
+	}
+	professionalCodes = []string{"99213", "99214", "99203", "99204", "36415", "93000", "20610", "97110", "90471", "99232"}
+	professionalPOS   = []string{"11", "11", "11", "22", "21", "19"}
+	anesthesiaCodes   = []string{"00790", "00840", "01402", "00400", "00731", "01967"}
+)
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rand.Intn(len(values))]
+}
+
+// amount returns a log-normally distributed billed amount with the given mean. Amounts are at least one cent.
+func (g *Generator) amount(mean float64) float64 {
+	sigma := g.opts.Sigma
+	mu := math.Log(mean) - sigma*sigma/2
+	return max(roundCents(math.Exp(mu+sigma*g.rand.NormFloat64())), 0.01)
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func (g *Generator) lines() int {
+	return g.opts.MinLines + g.rand.Intn(g.opts.MaxLines-g.opts.MinLines+1)
+}
+
+func (g *Generator) date() mph.Date {
+	start := time.Date(g.opts.Year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return mph.Date{Time: start.AddDate(0, 0, g.rand.Intn(365))}
+}
+
+// npi returns a random NPI with a valid check digit.
+func (g *Generator) npi() string {
+	base := fmt.Sprintf("%d%08d", 1+g.rand.Intn(2), g.rand.Intn(100000000))
+	return base + strconv.Itoa(npiCheckDigit(base))
+}
+
+// npiCheckDigit returns the Luhn check digit for the first 9 digits of an NPI using the 80840 prefix.
+func npiCheckDigit(base string) int {
+	sum := 24 // contribution of the 80840 prefix
+	for i := len(base) - 1; i >= 0; i-- {
+		d := int(base[i] - '0')
+		if (len(base)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// ValidNPI returns true if npi has 10 digits and a valid check digit.
+func ValidNPI(npi string) bool {
+	if len(npi) != 10 {
+		return false
+	}
+	for _, c := range npi {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return npiCheckDigit(npi[:9]) == int(npi[9]-'0')
+}
+
+// base returns a claim with a provider, patient and diagnoses.
+func (g *Generator) base(formType mph.FormType, minAge, maxAge int) mph.Claim {
+	location := locations[g.rand.Intn(len(locations))]
+	from := g.date()
+	age := minAge + g.rand.Intn(maxAge-minAge+1)
+	dob := mph.Date{Time: from.Time.AddDate(-age, 0, -g.rand.Intn(365))}
+	claim := mph.Claim{
+		Provider:           mph.Provider{NPI: g.npi(), ProviderZIP: location.zip, ProviderState: location.state},
+		ClaimID:            fmt.Sprintf("SYN-%08X", g.rand.Uint32()),
+		PatientSex:         mph.SexType(1 + g.rand.Intn(2)),
+		PatientDateOfBirth: &dob,
+		FormType:           formType,
+		DateFrom:           from,
+		DateThrough:        from,
+		PrincipalDiagnosis: &mph.Diagnosis{Code: g.pick(diagnoses)},
+	}
+	for range g.rand.Intn(4) {
+		claim.OtherDiagnoses = append(claim.OtherDiagnoses, mph.Diagnosis{Code: g.pick(diagnoses)})
+	}
+	if formType == mph.UBFormType {
+		claim.CCN = fmt.Sprintf("%02d%04d", 1+g.rand.Intn(50), 1+g.rand.Intn(899))
+	}
+	return claim
+}
+
+func (g *Generator) inpatient() mph.Claim {
+	claim := g.base(mph.UBFormType, 18, 95)
+	claim.BillTypeOrPOS = "111"
+	claim.DRG = g.pick(drgs)
+	claim.DischargeStatus = "01"
+	claim.DateThrough = mph.Date{Time: claim.DateFrom.Time.AddDate(0, 0, 1+g.rand.Intn(10))}
+	for range g.lines() {
+		claim.Services = append(claim.Services, mph.Service{
+			RevCode:      g.pick(inpatientRevs),
+			DateFrom:     claim.DateFrom,
+			DateThrough:  claim.DateThrough,
+			BilledAmount: g.amount(g.opts.MeanBilled * 12),
+			Quantity:     float64(1 + g.rand.Intn(5)),
+		})
+	}
+	return claim
+}
+
+func (g *Generator) outpatient() mph.Claim {
+	claim := g.base(mph.UBFormType, 0, 95)
+	claim.BillTypeOrPOS = "131"
+	for range g.lines() {
+		line := outpatientLines[g.rand.Intn(len(outpatientLines))]
+		claim.Services = append(claim.Services, mph.Service{
+			RevCode:       line.rev,
+			ProcedureCode: line.code,
+			DateFrom:      claim.DateFrom,
+			DateThrough:   claim.DateFrom,
+			BilledAmount:  g.amount(g.opts.MeanBilled * 3),
+			Quantity:      1,
+		})
+	}
+	return claim
+}
+
+func (g *Generator) professional() mph.Claim {
+	claim := g.base(mph.HCFAFormType, 0, 95)
+	claim.BillTypeOrPOS = g.pick(professionalPOS)
+	for range g.lines() {
+		service := mph.Service{
+			ProcedureCode: g.pick(professionalCodes),
+			DateFrom:      claim.DateFrom,
+			DateThrough:   claim.DateFrom,
+			BilledAmount:  g.amount(g.opts.MeanBilled),
+			Quantity:      1,
+			Units:         "UN",
+		}
+		if g.rand.Intn(5) == 0 {
+			service.ProcedureModifiers = []string{"25"}
+		}
+		claim.Services = append(claim.Services, service)
+	}
+	return claim
+}
+
+func (g *Generator) ambulance() mph.Claim {
+	claim := g.base(mph.HCFAFormType, 0, 95)
+	claim.BillTypeOrPOS = "41"
+	pickup := locations[g.rand.Intn(len(locations))]
+	claim.AmbulancePickupZIP = pickup.zip
+	miles := float64(1 + g.rand.Intn(40))
+	claim.PatientWeightInKG = float64(40 + g.rand.Intn(80))
+	claim.Services = []mph.Service{
+		{ProcedureCode: "A0427", ProcedureModifiers: []string{"RH"}, DateFrom: claim.DateFrom, DateThrough: claim.DateFrom, BilledAmount: g.amount(g.opts.MeanBilled * 4), Quantity: 1, Units: "UN"},
+		{ProcedureCode: "A0425", ProcedureModifiers: []string{"RH"}, DateFrom: claim.DateFrom, DateThrough: claim.DateFrom, BilledAmount: roundCents(miles * g.amount(g.opts.MeanBilled/10)), Quantity: miles, Units: "UN"},
+	}
+	return claim
+}
+
+func (g *Generator) anesthesia() mph.Claim {
+	claim := g.base(mph.HCFAFormType, 0, 95)
+	claim.BillTypeOrPOS = "22"
+	minutes := float64(15 + g.rand.Intn(226))
+	claim.Services = []mph.Service{{
+		ProcedureCode:      g.pick(anesthesiaCodes),
+		ProcedureModifiers: []string{"AA"},
+		DateFrom:           claim.DateFrom,
+		DateThrough:        claim.DateFrom,
+		BilledAmount:       g.amount(g.opts.MeanBilled * minutes / 15),
+		Quantity:           minutes,
+		Units:              "MJ",
+	}}
+	return claim
+}
+
+func (g *Generator) addDefect(claim *mph.Claim, defect Defect) {
+	switch defect {
+	case DefectMissingNPI:
+		claim.NPI = ""
+	case DefectInvalidNPI:
+		check := int(claim.NPI[9]-'0'+1) % 10
+		claim.NPI = claim.NPI[:9] + strconv.Itoa(check)
+	case DefectMissingProcedureCode:
+		claim.Services[0].RevCode = ""
+		claim.Services[0].ProcedureCode = ""
+	case DefectDatesReversed:
+		claim.DateThrough = mph.Date{Time: claim.DateFrom.Time.AddDate(0, 0, -1-g.rand.Intn(5))}
+	case DefectNegativeAmount:
+		claim.Services[0].BilledAmount = -claim.Services[0].BilledAmount
+	case DefectTotalMismatch:
+		claim.BilledAmount = roundCents(claim.BilledAmount * (1.5 + g.rand.Float64()))
+	case DefectMissingDiagnosis:
+		claim.PrincipalDiagnosis = nil
+		claim.OtherDiagnoses = nil
+	case DefectNoServices:
+		claim.Services = nil
+	}
+}
+
+// QuickClaim implements quick.Generator so that valid claims can be used as arguments to quick.Check. The size
+// passed by testing/quick limits the number of service lines.
+type QuickClaim struct {
+	mph.Claim
+}
+
+var _ quick.Generator = QuickClaim{}
+
+func (QuickClaim) Generate(r *rand.Rand, size int) reflect.Value {
+	g := newGenerator(r, Options{MaxLines: max(size, 1)})
+	return reflect.ValueOf(QuickClaim{g.Claim()})
+}
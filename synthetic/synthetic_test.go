@@ -0,0 +1,158 @@
+package synthetic
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/mypricehealth/mphgo/mph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkValid returns false if the claim breaks any of the rules followed by generated claims without defects.
+func checkValid(t *testing.T, claim mph.Claim) bool {
+	sum := 0.0
+	for _, service := range claim.Services {
+		sum += service.BilledAmount
+		if !assert.Positive(t, service.BilledAmount) || !assert.True(t, service.RevCode != "" || service.ProcedureCode != "") {
+			return false
+		}
+	}
+	return assert.True(t, ValidNPI(claim.NPI), claim.NPI) &&
+		assert.NotEmpty(t, claim.ClaimID) &&
+		assert.NotEmpty(t, claim.Services) &&
+		assert.NotNil(t, claim.PrincipalDiagnosis) &&
+		assert.False(t, claim.DateThrough.Time.Before(claim.DateFrom.Time)) &&
+		assert.InDelta(t, sum, claim.BilledAmount, 0.005)
+}
+
+func TestGenerator(t *testing.T) {
+	t.Parallel()
+	opts := Options{MinLines: 2, MaxLines: 4}
+	claims := New(42, opts).Claims(200)
+	assert.Equal(t, claims, New(42, opts).Claims(200), "the same seed should produce the same claims")
+	assert.NotEqual(t, claims[0], New(43, opts).Claim())
+
+	kinds := map[Kind]int{}
+	g := New(1, opts)
+	for range 500 {
+		sample := g.Next()
+		kinds[sample.Kind]++
+		assert.Equal(t, DefectNone, sample.Defect)
+		require.True(t, checkValid(t, sample.Claim))
+		claim := sample.Claim
+		switch sample.Kind {
+		case KindInpatient:
+			assert.Equal(t, mph.UBFormType, claim.FormType)
+			assert.NotEmpty(t, claim.DRG)
+			assert.True(t, claim.DateThrough.Time.After(claim.DateFrom.Time))
+			assert.LessOrEqual(t, len(claim.Services), 4)
+			assert.GreaterOrEqual(t, len(claim.Services), 2)
+		case KindOutpatient:
+			assert.Equal(t, "131", claim.BillTypeOrPOS)
+			assert.NotEmpty(t, claim.Services[0].RevCode)
+		case KindProfessional:
+			assert.Equal(t, mph.HCFAFormType, claim.FormType)
+		case KindAmbulance:
+			assert.Len(t, claim.AmbulancePickupZIP, 5)
+			assert.Equal(t, "A0425", claim.Services[1].ProcedureCode)
+		case KindAnesthesia:
+			assert.Equal(t, "MJ", claim.Services[0].Units)
+			assert.GreaterOrEqual(t, claim.Services[0].Quantity, 15.0)
+		}
+	}
+	assert.Len(t, kinds, len(Kinds))
+
+	claims = New(1, Options{Kinds: []Kind{KindProfessional}, MeanBilled: 100, Sigma: 0.5}).Claims(2000)
+	total, count := 0.0, 0
+	for _, claim := range claims {
+		for _, service := range claim.Services {
+			total += service.BilledAmount
+			count++
+		}
+	}
+	assert.InDelta(t, 100, total/float64(count), 5)
+}
+
+func TestGeneratorDefects(t *testing.T) {
+	t.Parallel()
+	g := New(7, Options{})
+	for _, defect := range Defects {
+		for _, kind := range Kinds {
+			claim := g.Generate(kind, defect).Claim
+			switch defect {
+			case DefectMissingNPI:
+				assert.Empty(t, claim.NPI)
+			case DefectInvalidNPI:
+				assert.Len(t, claim.NPI, 10)
+				assert.False(t, ValidNPI(claim.NPI))
+			case DefectMissingProcedureCode:
+				assert.Empty(t, claim.Services[0].RevCode+claim.Services[0].ProcedureCode)
+			case DefectDatesReversed:
+				assert.True(t, claim.DateThrough.Time.Before(claim.DateFrom.Time))
+			case DefectNegativeAmount:
+				assert.Negative(t, claim.Services[0].BilledAmount)
+			case DefectTotalMismatch:
+				sum := 0.0
+				for _, service := range claim.Services {
+					sum += service.BilledAmount
+				}
+				assert.Greater(t, claim.BilledAmount, sum)
+			case DefectMissingDiagnosis:
+				assert.Nil(t, claim.PrincipalDiagnosis)
+			case DefectNoServices:
+				assert.Empty(t, claim.Services)
+			}
+		}
+	}
+
+	g = New(7, Options{DefectRate: 0.5, Defects: []Defect{DefectNoServices}})
+	defects := 0
+	for range 1000 {
+		if sample := g.Next(); sample.Defect == DefectNoServices {
+			defects++
+			assert.Empty(t, sample.Claim.Services)
+		}
+	}
+	assert.InDelta(t, 500, defects, 60)
+}
+
+func TestValidNPI(t *testing.T) {
+	t.Parallel()
+	assert.True(t, ValidNPI("1234567893"))
+	assert.True(t, ValidNPI("1962999664"))
+	assert.False(t, ValidNPI("1234567890"))
+	assert.False(t, ValidNPI("123456789"))
+	assert.False(t, ValidNPI("12345678a3"))
+}
+
+func TestQuickClaim(t *testing.T) {
+	t.Parallel()
+	valid := func(claim QuickClaim) bool {
+		return checkValid(t, claim.Claim)
+	}
+	require.NoError(t, quick.Check(valid, nil))
+
+	roundTrip := func(claim QuickClaim) bool {
+		data, err := json.Marshal(claim.Claim)
+		if err != nil {
+			return false
+		}
+		var decoded mph.Claim
+		return json.Unmarshal(data, &decoded) == nil && assert.Equal(t, claim.Claim, decoded)
+	}
+	require.NoError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 50}))
+}
+
+func FuzzClaimFromSeed(f *testing.F) {
+	f.Add(int64(0))
+	f.Add(int64(42))
+	f.Add(int64(math.MaxInt64))
+	f.Fuzz(func(t *testing.T, seed int64) {
+		claim := ClaimFromSeed(seed, Options{})
+		checkValid(t, claim)
+		assert.Equal(t, claim, ClaimFromSeed(seed, Options{}))
+	})
+}